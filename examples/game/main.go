@@ -24,7 +24,9 @@ func main() {
 	fmt.Println("Press any key to start...")
 	fmt.Println()
 
-	game := input.NewGameInput(nil)
+	// Terminals report presses but not releases: infer releases from
+	// autorepeat timing so held movement keys stop when let go
+	game := input.NewGameInput(input.New(input.WithReleaseInference(0, 0)))
 	if err := game.Start(); err != nil {
 		panic(err)
 	}
@@ -56,42 +58,45 @@ func main() {
 	for {
 		<-ticker.C
 
+		// Take this frame's input; draining the queue each frame keeps
+		// edge queries such as ActionJustPressed per frame
+		frame := game.Update()
+
 		// Handle movement (P2 - multiple keys)
-		if game.IsActionPressed("move-up") {
+		if frame.IsActionPressed("move-up") {
 			y--
 		}
-		if game.IsActionPressed("move-down") {
+		if frame.IsActionPressed("move-down") {
 			y++
 		}
-		if game.IsActionPressed("move-left") {
+		if frame.IsActionPressed("move-left") {
 			x--
 		}
-		if game.IsActionPressed("move-right") {
+		if frame.IsActionPressed("move-right") {
 			x++
 		}
 
-		// Handle actions (P1 - basic bindings)
+		// Handle actions (P1 - basic bindings); one per key press, so
+		// holding the key does not repeat them
 		switch {
-		case game.IsActionPressed("jump"):
+		case frame.ActionJustPressed("jump"):
 			jumpCount++
 			fmt.Printf("\r🎮 Position: (%3d, %3d) | Jumps: %3d | Fires: %3d | [JUMP!]    ", x, y, jumpCount, fireCount)
-			time.Sleep(100 * time.Millisecond) // Debounce
-		case game.IsActionPressed("fire"):
+		case frame.ActionJustPressed("fire"):
 			fireCount++
 			fmt.Printf("\r🎮 Position: (%3d, %3d) | Jumps: %3d | Fires: %3d | [FIRE!]    ", x, y, jumpCount, fireCount)
-			time.Sleep(100 * time.Millisecond) // Debounce
 		default:
 			fmt.Printf("\r🎮 Position: (%3d, %3d) | Jumps: %3d | Fires: %3d               ", x, y, jumpCount, fireCount)
 		}
 
 		// P3: Settings menu (rebinding)
-		if game.IsActionPressed("menu") {
+		if frame.ActionJustPressed("menu") {
 			fmt.Println()
 			showSettingsMenu(game)
 		}
 
 		// Exit
-		if game.IsActionPressed("quit") {
+		if frame.ActionJustPressed("quit") {
 			fmt.Println("\n\n✅ Thanks for playing!")
 			break
 		}
@@ -11,7 +11,7 @@ import (
 // Before optimization: 256 B/op, 1 allocs/op (buffer allocation)
 // After optimization: 0 B/op, 0 allocs/op (sync.Pool reuse)
 func BenchmarkReadEventAllocations(b *testing.B) {
-	backend := newBackend().(*unixBackend)
+	backend := newBackend(newConfig(nil)).(*unixBackend)
 	if err := backend.Init(); err != nil {
 		b.Skip("Not a terminal environment")
 	}
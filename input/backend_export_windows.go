@@ -5,6 +5,6 @@ package input
 
 // NewTestBackend creates a backend instance for testing purposes.
 // This is exported for use in integration tests.
-func NewTestBackend() Backend {
-	return newBackend()
+func NewTestBackend(opts ...Option) Backend {
+	return newBackend(newConfig(opts))
 }
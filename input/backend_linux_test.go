@@ -0,0 +1,98 @@
+//go:build linux
+// +build linux
+
+package input
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// openPTY returns the master and slave ends of a new pseudo-terminal, or
+// skips the test when none is available.
+func openPTY(t *testing.T) (master, slave *os.File) {
+	t.Helper()
+	m, err := os.OpenFile("/dev/ptmx", os.O_RDWR|unix.O_NOCTTY, 0)
+	if err != nil {
+		t.Skipf("no pseudo-terminal: %v", err)
+	}
+	if err := unix.IoctlSetPointerInt(int(m.Fd()), unix.TIOCSPTLCK, 0); err != nil {
+		m.Close()
+		t.Skipf("unlock pseudo-terminal: %v", err)
+	}
+	n, err := unix.IoctlGetInt(int(m.Fd()), unix.TIOCGPTN)
+	if err != nil {
+		m.Close()
+		t.Skipf("pseudo-terminal number: %v", err)
+	}
+	s, err := os.OpenFile("/dev/pts/"+strconv.Itoa(n), os.O_RDWR|unix.O_NOCTTY, 0)
+	if err != nil {
+		m.Close()
+		t.Skipf("open pseudo-terminal: %v", err)
+	}
+	return m, s
+}
+
+// TestReadHangup validates that a terminal hanging up, as when an SSH
+// session drops, ends capture with io.EOF rather than a read error.
+func TestReadHangup(t *testing.T) {
+	t.Run("backend", func(t *testing.T) {
+		master, slave := openPTY(t)
+		defer slave.Close()
+
+		backend := newBackend(newConfig([]Option{WithFile(slave)}))
+		if err := backend.Init(); err != nil {
+			master.Close()
+			t.Fatalf("Init() error: %v", err)
+		}
+		defer backend.Restore()
+		master.Close()
+
+		result := make(chan error, 1)
+		go func() {
+			_, err := backend.ReadEvent()
+			result <- err
+		}()
+
+		select {
+		case err := <-result:
+			if err != io.EOF {
+				t.Errorf("ReadEvent() error = %v, want io.EOF", err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("ReadEvent() blocked after hangup")
+		}
+	})
+
+	t.Run("input", func(t *testing.T) {
+		master, slave := openPTY(t)
+		defer slave.Close()
+
+		in := New(WithFile(slave))
+		if err := in.Start(); err != nil {
+			master.Close()
+			t.Fatalf("Start() error: %v", err)
+		}
+		defer in.Stop()
+		master.Close()
+
+		select {
+		case <-in.Done():
+		case <-time.After(time.Second):
+			t.Fatal("capture did not end after hangup")
+		}
+		err := in.Err()
+		if !errors.Is(err, ErrInputClosed) || !errors.Is(err, io.EOF) {
+			t.Errorf("Err() = %v, want ErrInputClosed and io.EOF", err)
+		}
+		if errors.Is(err, ErrTooManyErrors) {
+			t.Errorf("Err() = %v, hangup reported as failing reads", err)
+		}
+	})
+}
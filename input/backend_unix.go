@@ -0,0 +1,364 @@
+//go:build linux || darwin
+// +build linux darwin
+
+package input
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// readBufferPool provides reusable 256-byte buffers for reading terminal input.
+// This eliminates per-keypress allocations and reduces garbage collection pressure.
+var readBufferPool = sync.Pool{
+	New: func() interface{} {
+		b := make([]byte, 256)
+		return &b
+	},
+}
+
+// unixBackend implements the Backend interface for Unix-like systems
+// using termios for raw mode terminal control.
+type unixBackend struct {
+	fd            int
+	originalState *unix.Termios
+	parser        *SequenceParser
+	file          *os.File
+	initialized   bool
+
+	// source is the file input is read from unless a terminal had to be
+	// opened from ttyPath; tty is that terminal, open between Init and
+	// Restore. An empty ttyPath disables the fallback.
+	source  *os.File
+	ttyPath string
+	tty     *os.File
+
+	// escapeTimeout is how long to wait for the rest of a partial sequence.
+	escapeTimeout time.Duration
+
+	// protocols are enabled by Init and disabled by Restore.
+	protocols []Protocol
+
+	// rawState is the raw mode set by Init, re-applied after a suspend.
+	rawState *unix.Termios
+
+	// jobControl suspends the process on Ctrl+Z (see WithJobControl).
+	jobControl bool
+
+	// wakeR and wakeW are the self-pipe written by Cancel (-1 when not
+	// open); waitFds is reused by wait to avoid allocating.
+	wakeR, wakeW int
+	waitFds      waitSet
+
+	// pendingBuf accumulates partial UTF-8 sequences and escape codes across Read() calls.
+	// This is critical for handling multi-byte UTF-8 characters and escape sequences that
+	// may be split across multiple terminal read operations (e.g., on slow SSH connections).
+	pendingBuf []byte
+}
+
+// newBackend creates a new platform-specific backend.
+// On Unix systems, this returns a Unix backend.
+func newBackend(cfg config) Backend {
+	file, ttyPath := cfg.file, cfg.ttyPath
+	switch {
+	case file != nil:
+		// The caller chose the input; do not substitute another
+		ttyPath = ""
+	case ttyPath == "":
+		ttyPath = defaultTTYPath
+	}
+	if file == nil {
+		file = os.Stdin
+	}
+	return &unixBackend{
+		fd:            fileDescriptor(file),
+		parser:        newSequenceParser(cfg),
+		file:          file,
+		source:        file,
+		ttyPath:       ttyPath,
+		escapeTimeout: cfg.escapeTimeout,
+		protocols:     cfg.protocols,
+		jobControl:    cfg.jobControl,
+		wakeR:         -1,
+		wakeW:         -1,
+	}
+}
+
+// defaultTTYPath is the controlling terminal, opened when stdin is not
+// a terminal.
+const defaultTTYPath = "/dev/tty"
+
+// fileDescriptor returns the descriptor of f. Unlike f.Fd it does not
+// switch f to blocking mode behind the caller's back; read copes with a
+// non-blocking descriptor.
+func fileDescriptor(f *os.File) int {
+	conn, err := f.SyscallConn()
+	if err != nil {
+		return int(f.Fd())
+	}
+	fd := -1
+	_ = conn.Control(func(d uintptr) {
+		fd = int(d)
+	})
+	return fd
+}
+
+// Init initializes the backend by saving the current terminal state
+// and entering raw mode. This allows reading individual keypresses
+// without line buffering or echo.
+// Idempotent: calling multiple times is safe and does nothing after first call.
+func (b *unixBackend) Init() error {
+	// Idempotency check: if already initialized, do nothing
+	if b.initialized {
+		return nil
+	}
+
+	// Get current terminal state
+	state, err := unix.IoctlGetTermios(b.fd, ioctlGetTermios)
+	if err != nil && b.ttyPath != "" {
+		// Input is redirected: read keys from the controlling terminal
+		state, err = b.openTTY()
+		if err != nil {
+			return &TerminalError{Path: b.ttyPath, Err: err}
+		}
+	}
+	if err != nil {
+		return &TerminalError{Path: b.file.Name(), Err: err}
+	}
+
+	// Save original state for restoration
+	b.originalState = state
+
+	// Create raw mode state
+	rawState := *state
+
+	// Disable canonical mode (line buffering)
+	rawState.Lflag &^= unix.ICANON
+
+	// Disable echo
+	rawState.Lflag &^= unix.ECHO
+
+	// Disable signal generation (Ctrl+C, Ctrl+Z, etc.)
+	rawState.Lflag &^= unix.ISIG
+
+	// Disable extended input processing
+	rawState.Lflag &^= unix.IEXTEN
+
+	// Disable input parity checking
+	rawState.Iflag &^= unix.INPCK
+
+	// Disable stripping 8th bit
+	rawState.Iflag &^= unix.ISTRIP
+
+	// Disable CR-to-NL translation
+	rawState.Iflag &^= unix.ICRNL
+
+	// Disable output processing
+	rawState.Oflag &^= unix.OPOST
+
+	// Set character size to 8 bits
+	rawState.Cflag &^= unix.CSIZE
+	rawState.Cflag |= unix.CS8
+
+	// Set minimum characters to 1 (blocking read - wait for at least 1 byte)
+	rawState.Cc[unix.VMIN] = 1
+
+	// Set timeout to 0 (no inter-byte timeout); read waits for input
+	// with its own timeout before reading
+	rawState.Cc[unix.VTIME] = 0
+
+	// Apply raw mode
+	if err := b.setTermios(&rawState); err != nil {
+		b.originalState = nil
+		b.closeTTY()
+		return fmt.Errorf("failed to set raw mode: %w", err)
+	}
+	b.rawState = &rawState
+
+	// Self-pipe for Cancel
+	if err := b.openWake(); err != nil {
+		_ = b.setTermios(b.originalState)
+		b.originalState = nil
+		b.closeTTY()
+		return fmt.Errorf("failed to create wake pipe: %w", err)
+	}
+
+	// Mark as initialized to ensure idempotency
+	b.initialized = true
+
+	// Enable requested input protocols now that the terminal is in raw mode
+	b.writeProtocols(true)
+
+	return nil
+}
+
+// Restore restores the original terminal state.
+// This should be called when shutting down to return the terminal
+// to its normal operating mode. The backend can be initialized again
+// afterwards.
+func (b *unixBackend) Restore() error {
+	if b.originalState == nil {
+		// Nothing to restore (Init was never called)
+		return nil
+	}
+
+	// Disable protocols before leaving raw mode
+	if b.initialized {
+		b.writeProtocols(false)
+	}
+
+	err := b.setTermios(b.originalState)
+
+	// Forget the session, so a later Init enters raw mode again
+	b.closeWake()
+	b.initialized = false
+	b.pendingBuf = b.pendingBuf[:0]
+	b.parser.reset()
+
+	// Close a terminal opened by Init; its saved state goes with it
+	if b.tty != nil {
+		b.originalState = nil
+		b.closeTTY()
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to restore terminal state: %w", err)
+	}
+
+	return nil
+}
+
+// resetTerminal restores the saved terminal state and turns off protocols
+// and application modes, for a process that is about to die. It only
+// reads state set by Init, so it is safe to call while ReadEvent runs.
+func (b *unixBackend) resetTerminal() {
+	if !b.initialized {
+		return
+	}
+	b.writeProtocols(false)
+	_, _ = b.file.Write([]byte(terminalResetSequence))
+	_ = b.setTermios(b.originalState)
+}
+
+// setTermios applies state to the terminal.
+func (b *unixBackend) setTermios(state *unix.Termios) error {
+	return unix.IoctlSetTermios(b.fd, ioctlSetTermios, state)
+}
+
+// openTTY opens ttyPath, switches input to it and returns its terminal
+// state.
+func (b *unixBackend) openTTY() (*unix.Termios, error) {
+	tty, err := os.OpenFile(b.ttyPath, os.O_RDWR, 0)
+	if err != nil {
+		return nil, err
+	}
+	fd := fileDescriptor(tty)
+	state, err := unix.IoctlGetTermios(fd, ioctlGetTermios)
+	if err != nil {
+		_ = tty.Close()
+		return nil, err
+	}
+	b.tty, b.file, b.fd = tty, tty, fd
+	return state, nil
+}
+
+// closeTTY closes the terminal opened by openTTY, if any, and switches
+// input back to the source file.
+func (b *unixBackend) closeTTY() {
+	if b.tty == nil {
+		return
+	}
+	_ = b.tty.Close()
+	b.tty = nil
+	b.file, b.fd = b.source, fileDescriptor(b.source)
+	b.pendingBuf = b.pendingBuf[:0]
+}
+
+// ReadEvent reads a single event from the terminal.
+// It waits for input with poll(2), so Cancel can interrupt it at any time,
+// and handles multi-byte escape sequences: a partial sequence is completed
+// by input arriving within the escape timeout, or decoded as it stands.
+//
+// A single read may return several sequences (e.g. the key-down and key-up
+// reports of win32 input mode); bytes beyond the first sequence are kept
+// in pendingBuf and returned by subsequent calls without blocking.
+func (b *unixBackend) ReadEvent() (Event, error) {
+	// Get buffer from pool
+	bufPtr := readBufferPool.Get().(*[]byte)
+	defer readBufferPool.Put(bufPtr)
+	buf := *bufPtr
+
+	flush := false
+	for {
+		// Decode the next sequence if one is already buffered. After the
+		// escape timeout, a partial sequence is decoded as it stands: a
+		// lone ESC becomes KeyEscape, truncated sequences KeyUnknown.
+		if len(b.pendingBuf) > 0 {
+			event, n, err := b.parser.decode(b.pendingBuf, flush)
+			if n > 0 {
+				b.consume(n)
+				flush = false
+				if errors.Is(err, errNoEvent) {
+					continue
+				}
+				if err == nil && b.jobControl && event.Key == KeyCtrlZ {
+					if event.Pressed && !event.Repeat {
+						return b.suspend()
+					}
+					// The key-up and autorepeats of a Ctrl+Z that
+					// suspended the process
+					continue
+				}
+				return event, err
+			}
+		}
+
+		// Block until input arrives; with a partial sequence buffered, wait
+		// at most the escape timeout for the rest of it
+		timeout := time.Duration(-1)
+		if len(b.pendingBuf) > 0 {
+			timeout = b.escapeTimeout
+		}
+		n, err := b.read(buf, timeout)
+		if err != nil && len(b.pendingBuf) == 0 {
+			return Event{}, err
+		}
+
+		if n > 0 {
+			// CRITICAL: Copy data to persistent buffer before returning pooled buffer
+			b.pendingBuf = append(b.pendingBuf, buf[:n]...)
+		}
+
+		// Timeout or error: no more bytes are coming for now
+		flush = err != nil || n == 0
+	}
+}
+
+// writeProtocols writes the enable or disable sequences of the configured
+// protocols to the terminal; protocols are disabled in reverse order.
+// Write errors are ignored: a terminal that cannot be written to cannot
+// have had the protocols enabled either.
+func (b *unixBackend) writeProtocols(enable bool) {
+	var out []byte
+	for i := range b.protocols {
+		if enable {
+			out = append(out, b.protocols[i].enableSequence()...)
+		} else {
+			out = append(out, b.protocols[len(b.protocols)-1-i].disableSequence()...)
+		}
+	}
+	if len(out) > 0 {
+		_, _ = b.file.Write(out)
+	}
+}
+
+// consume removes the first n bytes from pendingBuf, keeping its storage.
+func (b *unixBackend) consume(n int) {
+	rest := copy(b.pendingBuf, b.pendingBuf[n:])
+	b.pendingBuf = b.pendingBuf[:rest]
+}
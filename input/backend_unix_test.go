@@ -0,0 +1,278 @@
+//go:build !windows
+// +build !windows
+
+package input
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// TestWithFile validates that the Unix backend reads from the configured
+// file instead of stdin.
+func TestWithFile(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	backend := newBackend(newConfig([]Option{WithFile(r)}))
+	if _, err := w.Write([]byte("\x1b[Ax")); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []Key{KeyUp, KeyX} {
+		event, err := backend.ReadEvent()
+		if err != nil {
+			t.Fatalf("ReadEvent() error: %v", err)
+		}
+		if event.Key != want {
+			t.Errorf("ReadEvent().Key = %v, want %v", event.Key, want)
+		}
+	}
+}
+
+// TestWithEscapeTimeout validates that a lone ESC is decoded as the Escape
+// key once the configured escape timeout expires.
+func TestWithEscapeTimeout(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	const timeout = 100 * time.Millisecond
+	backend := newBackend(newConfig([]Option{WithFile(r), WithEscapeTimeout(timeout)}))
+	if _, err := w.Write([]byte{0x1b}); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	event, err := backend.ReadEvent()
+	if err != nil {
+		t.Fatalf("ReadEvent() error: %v", err)
+	}
+	if event.Key != KeyEscape {
+		t.Errorf("ReadEvent().Key = %v, want KeyEscape", event.Key)
+	}
+	if elapsed := time.Since(start); elapsed < timeout {
+		t.Errorf("ESC decoded after %v, want at least %v", elapsed, timeout)
+	}
+}
+
+// TestInitNotTerminal validates that Init reports a TerminalError matching
+// ErrNotTerminal when input is not a terminal and no fallback is possible.
+func TestInitNotTerminal(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	missing := filepath.Join(t.TempDir(), "no-tty")
+
+	tests := []struct {
+		name     string
+		opts     []Option
+		wantPath string
+	}{
+		// WithFile disables the fallback, so the pipe itself is reported
+		{"explicit file", []Option{WithFile(r), WithTTYPath(missing)}, r.Name()},
+		// Redirected stdin falls back to the tty path, which is missing
+		{"missing tty", []Option{WithTTYPath(missing)}, missing},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := newBackend(newConfig(tt.opts)).(*unixBackend)
+			// Stand in for a redirected stdin
+			backend.file, backend.source, backend.fd = r, r, fileDescriptor(r)
+
+			err := backend.Init()
+			if !errors.Is(err, ErrNotTerminal) {
+				t.Fatalf("Init() error = %v, want ErrNotTerminal", err)
+			}
+			var termErr *TerminalError
+			if !errors.As(err, &termErr) || termErr.Path != tt.wantPath {
+				t.Errorf("Init() error = %#v, want TerminalError for %s", err, tt.wantPath)
+			}
+			if backend.tty != nil || backend.file != r {
+				t.Error("Init() failure should leave input on the source file")
+			}
+			if err := backend.Restore(); err != nil {
+				t.Errorf("Restore() after failed Init error = %v", err)
+			}
+		})
+	}
+}
+
+// TestJobControl validates that Ctrl+Z suspends the process once when job
+// control is enabled and is delivered as KeyCtrlZ otherwise.
+func TestJobControl(t *testing.T) {
+	stops := 0
+	defer func(orig func() error) { raiseStop = orig }(raiseStop)
+	raiseStop = func() error {
+		// Continue immediately instead of stopping the test binary
+		stops++
+		return unix.Kill(unix.Getpid(), unix.SIGCONT)
+	}
+
+	tests := []struct {
+		name      string
+		opts      []Option
+		input     string
+		wantType  EventType
+		wantKey   Key
+		wantStops int
+	}{
+		{"disabled", nil, "\x1aa", EventKey, KeyCtrlZ, 0},
+		{"enabled", []Option{WithJobControl()}, "\x1aa", EventResume, KeyUnknown, 1},
+		{"win32 key-up", []Option{WithJobControl()},
+			"\x1b[90;44;26;1;8;1_\x1b[90;44;26;0;8;1_a", EventResume, KeyUnknown, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stops = 0
+			r, w, err := os.Pipe()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer r.Close()
+			defer w.Close()
+
+			backend := newBackend(newConfig(append(tt.opts, WithFile(r))))
+			if _, err := w.Write([]byte(tt.input)); err != nil {
+				t.Fatal(err)
+			}
+
+			event, err := backend.ReadEvent()
+			if err != nil {
+				t.Fatalf("ReadEvent() error: %v", err)
+			}
+			if event.Type != tt.wantType || event.Key != tt.wantKey {
+				t.Errorf("ReadEvent() = {Type:%v Key:%v}, want {Type:%v Key:%v}",
+					event.Type, event.Key, tt.wantType, tt.wantKey)
+			}
+			if stops != tt.wantStops {
+				t.Errorf("process stopped %d times, want %d", stops, tt.wantStops)
+			}
+
+			// Input typed after Ctrl+Z is not lost
+			event, err = backend.ReadEvent()
+			if err != nil || event.Key != KeyA {
+				t.Errorf("ReadEvent() after Ctrl+Z = (%v, %v), want KeyA", event.Key, err)
+			}
+		})
+	}
+}
+
+// TestCancel validates that Cancel interrupts a ReadEvent blocked waiting
+// for input, and that later calls keep returning io.EOF.
+func TestCancel(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	backend := newBackend(newConfig([]Option{WithFile(r)})).(*unixBackend)
+	if err := backend.openWake(); err != nil {
+		t.Fatalf("openWake() error: %v", err)
+	}
+	defer backend.closeWake()
+
+	result := make(chan error, 1)
+	go func() {
+		_, err := backend.ReadEvent()
+		result <- err
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	backend.Cancel()
+
+	select {
+	case err := <-result:
+		if !errors.Is(err, io.EOF) {
+			t.Errorf("ReadEvent() after Cancel error = %v, want io.EOF", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("ReadEvent() did not return after Cancel")
+	}
+
+	if _, err := backend.ReadEvent(); !errors.Is(err, io.EOF) {
+		t.Errorf("second ReadEvent() error = %v, want io.EOF", err)
+	}
+}
+
+// TestDiscardTypeahead validates that input waiting when the terminal is
+// taken back is dropped, while later input is read normally.
+func TestDiscardTypeahead(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	backend := newBackend(newConfig([]Option{WithFile(r)})).(*unixBackend)
+	if _, err := w.Write([]byte(":wq\r")); err != nil {
+		t.Fatal(err)
+	}
+	backend.discardTypeahead()
+
+	if _, err := w.Write([]byte("x")); err != nil {
+		t.Fatal(err)
+	}
+	event, err := backend.ReadEvent()
+	if err != nil || event.Key != KeyX {
+		t.Errorf("ReadEvent() after discard = (%v, %v), want KeyX", event.Key, err)
+	}
+}
+
+// TestReadInvalidDescriptor validates that read reports a descriptor that
+// is no longer open as EBADF rather than waiting for input.
+func TestReadInvalidDescriptor(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	fd, err := unix.Dup(int(r.Fd()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend := newBackend(newConfig([]Option{WithFile(r)})).(*unixBackend)
+	backend.fd = fd
+	if err := unix.Close(fd); err != nil {
+		t.Fatal(err)
+	}
+
+	result := make(chan error, 1)
+	go func() {
+		_, err := backend.read(make([]byte, 16), -1)
+		result <- err
+	}()
+
+	select {
+	case err := <-result:
+		if !errors.Is(err, unix.EBADF) {
+			t.Errorf("read() error = %v, want EBADF", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("read() blocked on an invalid descriptor")
+	}
+}
@@ -17,9 +17,9 @@ type windowsBackend struct {
 
 // newBackend creates a new platform-specific backend.
 // On Windows systems, this returns a Windows backend stub.
-func newBackend() Backend {
+func newBackend(cfg config) Backend {
 	return &windowsBackend{
-		parser: NewSequenceParser(),
+		parser: newSequenceParser(cfg),
 	}
 }
 
@@ -46,6 +46,7 @@ func (b *windowsBackend) Restore() error {
 	}
 
 	// TODO: Implement console mode restoration
+	b.initialized = false
 	return nil
 }
 
@@ -58,3 +59,9 @@ func (b *windowsBackend) ReadEvent() (Event, error) {
 	// - Normalize to Event
 	return Event{}, errors.New("windows backend not yet implemented")
 }
+
+// Cancel interrupts a blocked ReadEvent.
+// Currently a no-op as ReadEvent does not block.
+func (b *windowsBackend) Cancel() {
+	// TODO: Signal an event object waited on alongside the console handle
+}
@@ -0,0 +1,246 @@
+package input
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Charset decodes text bytes sent by the terminal into runes.
+// Escape sequences and control characters are always ASCII and are
+// recognized before text is handed to the Charset, so only bytes that
+// start a character (including bytes >= 0x80) are decoded through it.
+//
+// The method set mirrors the unicode/utf8 package. A Charset may be shared
+// by several Inputs (for example through RegisterCharset and
+// WithLocaleCharset), so implementations must be safe for concurrent use.
+type Charset interface {
+	// DecodeRune decodes the first character in p and returns the rune
+	// and its width in bytes. Invalid input returns (utf8.RuneError, 1).
+	DecodeRune(p []byte) (r rune, size int)
+
+	// FullRune reports whether p begins with a complete character.
+	// An invalid encoding is considered complete, since DecodeRune will
+	// consume it as a single error byte.
+	FullRune(p []byte) bool
+}
+
+// Built-in charsets.
+var (
+	// UTF8 is the default charset.
+	UTF8 Charset = utf8Charset{}
+
+	// Latin1 is ISO-8859-1: every byte is the code point of the same value.
+	Latin1 Charset = latin1Charset{}
+
+	// Windows1252 is the Windows code page 1252 superset of Latin-1, with
+	// printable characters in 0x80-0x9f.
+	Windows1252 Charset = windows1252Charset{}
+)
+
+type utf8Charset struct{}
+
+func (utf8Charset) DecodeRune(p []byte) (rune, int) { return utf8.DecodeRune(p) }
+func (utf8Charset) FullRune(p []byte) bool          { return utf8.FullRune(p) }
+
+type latin1Charset struct{}
+
+func (latin1Charset) DecodeRune(p []byte) (rune, int) {
+	if len(p) == 0 {
+		return utf8.RuneError, 0
+	}
+	return rune(p[0]), 1
+}
+
+func (latin1Charset) FullRune(p []byte) bool { return len(p) > 0 }
+
+// windows1252High maps bytes 0x80-0x9f. Bytes that are undefined in the
+// code page map to the C1 control of the same value, as browsers do.
+var windows1252High = [32]rune{
+	0x20ac, 0x0081, 0x201a, 0x0192, 0x201e, 0x2026, 0x2020, 0x2021,
+	0x02c6, 0x2030, 0x0160, 0x2039, 0x0152, 0x008d, 0x017d, 0x008f,
+	0x0090, 0x2018, 0x2019, 0x201c, 0x201d, 0x2022, 0x2013, 0x2014,
+	0x02dc, 0x2122, 0x0161, 0x203a, 0x0153, 0x009d, 0x017e, 0x0178,
+}
+
+type windows1252Charset struct{}
+
+func (windows1252Charset) DecodeRune(p []byte) (rune, int) {
+	if len(p) == 0 {
+		return utf8.RuneError, 0
+	}
+	if b := p[0]; b >= 0x80 && b <= 0x9f {
+		return windows1252High[b-0x80], 1
+	}
+	return rune(p[0]), 1
+}
+
+func (windows1252Charset) FullRune(p []byte) bool { return len(p) > 0 }
+
+// Transformer is the subset of golang.org/x/text/transform.Transformer used
+// by NewTransformCharset. Decoders from golang.org/x/text/encoding (for
+// example japanese.ShiftJIS.NewDecoder() or simplifiedchinese.GBK.NewDecoder())
+// satisfy it.
+type Transformer interface {
+	Transform(dst, src []byte, atEOF bool) (nDst, nSrc int, err error)
+	Reset()
+}
+
+// NewTransformCharset adapts a decoder that converts a legacy encoding to
+// UTF-8 into a Charset. maxLen is the longest byte sequence of a single
+// character in the encoding (2 for Shift-JIS and GBK, 4 for GB18030).
+//
+// The decoder is shared by every Input using the Charset; calls are
+// serialized, so t need not be safe for concurrent use.
+//
+// This keeps gokeys free of a golang.org/x/text dependency while allowing
+// multi-byte CJK encodings:
+//
+//	sjis := input.NewTransformCharset(japanese.ShiftJIS.NewDecoder(), 2)
+//	input.RegisterCharset(sjis, "Shift_JIS", "SJIS")
+//	in := input.New(input.WithLocaleCharset())
+func NewTransformCharset(t Transformer, maxLen int) Charset {
+	if maxLen < 1 {
+		maxLen = 1
+	}
+	return &transformCharset{t: t, maxLen: maxLen}
+}
+
+type transformCharset struct {
+	maxLen int
+
+	// mu guards the decoder state and dst, which all users share.
+	mu  sync.Mutex
+	t   Transformer
+	dst [utf8.UTFMax * 2]byte
+}
+
+// decode tries successively longer prefixes of p until the decoder
+// consumes one completely.
+func (c *transformCharset) decode(p []byte) (r rune, size int, full bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for n := 1; n <= len(p) && n <= c.maxLen; n++ {
+		c.t.Reset()
+		nDst, nSrc, _ := c.t.Transform(c.dst[:], p[:n], false)
+		if nSrc == n && nDst > 0 {
+			r, _ := utf8.DecodeRune(c.dst[:nDst])
+			return r, n, true
+		}
+	}
+	if len(p) < c.maxLen {
+		return utf8.RuneError, 0, false
+	}
+	return utf8.RuneError, 1, true
+}
+
+func (c *transformCharset) DecodeRune(p []byte) (rune, int) {
+	if len(p) == 0 {
+		return utf8.RuneError, 0
+	}
+	r, size, full := c.decode(p)
+	if !full {
+		return utf8.RuneError, 1
+	}
+	return r, size
+}
+
+func (c *transformCharset) FullRune(p []byte) bool {
+	if len(p) == 0 {
+		return false
+	}
+	_, _, full := c.decode(p)
+	return full
+}
+
+// charsets maps normalized charset names to Charsets.
+var (
+	charsetsMu sync.RWMutex
+	charsets   = map[string]Charset{
+		"utf8":        UTF8,
+		"iso88591":    Latin1,
+		"latin1":      Latin1,
+		"88591":       Latin1,
+		"cp1252":      Windows1252,
+		"windows1252": Windows1252,
+	}
+)
+
+// normalizeCharsetName lowercases name and strips punctuation, so
+// "UTF-8", "utf8" and "Utf_8" are equivalent.
+func normalizeCharsetName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if r != '-' && r != '_' && r != ' ' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// RegisterCharset makes cs available under the given names for
+// LookupCharset and locale detection. Names are matched case-insensitively,
+// ignoring '-' and '_'. Registering an existing name replaces it.
+func RegisterCharset(cs Charset, names ...string) {
+	charsetsMu.Lock()
+	defer charsetsMu.Unlock()
+	for _, name := range names {
+		charsets[normalizeCharsetName(name)] = cs
+	}
+}
+
+// LookupCharset returns the Charset registered under name.
+func LookupCharset(name string) (Charset, bool) {
+	charsetsMu.RLock()
+	defer charsetsMu.RUnlock()
+	cs, ok := charsets[normalizeCharsetName(name)]
+	return cs, ok
+}
+
+// CharsetFromLocale returns the Charset named by the codeset of the
+// current locale, taken from the first non-empty of LC_ALL, LC_CTYPE and
+// LANG (e.g. "ja_JP.SJIS"). It returns UTF8 when no codeset is set or the
+// codeset is not registered; use LocaleCharset to tell the two apart.
+func CharsetFromLocale() Charset {
+	cs, _ := LocaleCharset()
+	return cs
+}
+
+// LocaleCharset is like CharsetFromLocale, but reports a codeset that is
+// not registered with an error matching ErrUnknownCharset, together with
+// UTF8. Only UTF-8, Latin-1 and Windows-1252 are built in; multi-byte
+// encodings such as Shift_JIS or GBK must be registered first (see
+// NewTransformCharset and RegisterCharset).
+func LocaleCharset() (Charset, error) {
+	for _, env := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		locale := os.Getenv(env)
+		if locale == "" {
+			continue
+		}
+		codeset := localeCodeset(locale)
+		if codeset == "" {
+			return UTF8, nil
+		}
+		if cs, ok := LookupCharset(codeset); ok {
+			return cs, nil
+		}
+		return UTF8, fmt.Errorf("%w: %q (locale %s=%s)", ErrUnknownCharset, codeset, env, locale)
+	}
+	return UTF8, nil
+}
+
+// localeCodeset extracts the codeset from a locale name of the form
+// language[_territory][.codeset][@modifier].
+func localeCodeset(locale string) string {
+	if i := strings.IndexByte(locale, '@'); i >= 0 {
+		locale = locale[:i]
+	}
+	i := strings.IndexByte(locale, '.')
+	if i < 0 {
+		return ""
+	}
+	return locale[i+1:]
+}
@@ -0,0 +1,188 @@
+package input
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"unicode/utf8"
+)
+
+// TestCharsetDecoding validates that text bytes are decoded through the
+// configured charset while escape sequences keep working.
+func TestCharsetDecoding(t *testing.T) {
+	tests := []struct {
+		name     string
+		charset  Charset
+		seq      string
+		wantKey  Key
+		wantRune rune
+	}{
+		{"Latin-1 e-acute", Latin1, "\xe9", KeyUnknown, 'é'},
+		{"Latin-1 C1 range", Latin1, "\x85", KeyUnknown, 0x85},
+		{"CP1252 euro", Windows1252, "\x80", KeyUnknown, '€'},
+		{"CP1252 quote", Windows1252, "\x93", KeyUnknown, '“'},
+		{"CP1252 Latin-1 range", Windows1252, "\xfc", KeyUnknown, 'ü'},
+		{"Latin-1 ASCII", Latin1, "a", KeyA, 'a'},
+		{"Latin-1 escape sequence", Latin1, "\x1b[A", KeyUp, 0},
+		{"UTF-8 explicit", UTF8, "\xc3\xa9", KeyUnknown, 'é'},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewSequenceParser(WithCharset(tt.charset))
+			buf := []byte(tt.seq)
+
+			if n, complete := p.split(buf); !complete || n != len(buf) {
+				t.Fatalf("split(%q) = (%d, %v), want (%d, true)", tt.seq, n, complete, len(buf))
+			}
+
+			event, err := p.Parse(buf)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if event.Key != tt.wantKey {
+				t.Errorf("Key = %v, want %v", event.Key, tt.wantKey)
+			}
+			if event.Rune != tt.wantRune {
+				t.Errorf("Rune = %q, want %q", event.Rune, tt.wantRune)
+			}
+		})
+	}
+}
+
+// doubleByteTransformer is a minimal stand-in for an x/text decoder of a
+// double-byte encoding: bytes >= 0x81 lead a two-byte character that
+// decodes to U+4E00 plus the trail byte.
+type doubleByteTransformer struct{}
+
+type errShortSrc struct{}
+
+func (errShortSrc) Error() string { return "short source" }
+
+func (doubleByteTransformer) Reset() {}
+
+func (doubleByteTransformer) Transform(dst, src []byte, atEOF bool) (int, int, error) {
+	nDst, nSrc := 0, 0
+	for nSrc < len(src) {
+		b := src[nSrc]
+		if b < 0x81 {
+			dst[nDst] = b
+			nDst++
+			nSrc++
+			continue
+		}
+		if nSrc+1 >= len(src) {
+			return nDst, nSrc, errShortSrc{}
+		}
+		nDst += utf8.EncodeRune(dst[nDst:], 0x4e00+rune(src[nSrc+1]))
+		nSrc += 2
+	}
+	return nDst, nSrc, nil
+}
+
+// TestTransformCharset validates the adapter for x/text style decoders,
+// including characters whose trail byte is in the ASCII range.
+func TestTransformCharset(t *testing.T) {
+	cs := NewTransformCharset(doubleByteTransformer{}, 2)
+	p := NewSequenceParser(WithCharset(cs))
+
+	if n, complete := p.split([]byte{0x88}); complete {
+		t.Errorf("split(lead byte) = (%d, true), want incomplete", n)
+	}
+
+	buf := []byte{0x88, 'A', 'b'}
+	n, complete := p.split(buf)
+	if !complete || n != 2 {
+		t.Fatalf("split() = (%d, %v), want (2, true)", n, complete)
+	}
+
+	event, err := p.Parse(buf[:n])
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if want := rune(0x4e00 + 'A'); event.Rune != want {
+		t.Errorf("Rune = %U, want %U", event.Rune, want)
+	}
+}
+
+// TestTransformCharsetShared validates that one transform Charset can be
+// used by several parsers at once, as it is after RegisterCharset.
+func TestTransformCharsetShared(t *testing.T) {
+	cs := NewTransformCharset(doubleByteTransformer{}, 2)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		trail := byte('A' + i)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p := NewSequenceParser(WithCharset(cs))
+			for j := 0; j < 1000; j++ {
+				event, err := p.Parse([]byte{0x88, trail})
+				if err != nil {
+					t.Errorf("Parse() error = %v", err)
+					return
+				}
+				if want := 0x4e00 + rune(trail); event.Rune != want {
+					t.Errorf("Rune = %U, want %U", event.Rune, want)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// TestCharsetFromLocale validates codeset detection from locale variables,
+// and that unregistered codesets are reported by LocaleCharset.
+func TestCharsetFromLocale(t *testing.T) {
+	tests := []struct {
+		name        string
+		lcAll       string
+		lcType      string
+		lang        string
+		want        Charset
+		wantUnknown bool
+	}{
+		{"unset", "", "", "", UTF8, false},
+		{"UTF-8 LANG", "", "", "en_US.UTF-8", UTF8, false},
+		{"Latin-1 LANG", "", "", "de_DE.ISO-8859-1", Latin1, false},
+		{"modifier", "", "", "fr_FR.ISO8859-1@euro", Latin1, false},
+		{"LC_CTYPE overrides LANG", "", "en_US.CP1252", "en_US.UTF-8", Windows1252, false},
+		{"LC_ALL overrides all", "en_US.UTF-8", "en_US.CP1252", "de_DE.ISO-8859-1", UTF8, false},
+		{"unregistered falls back", "", "", "ja_JP.EUC-JP", UTF8, true},
+		{"unregistered Shift_JIS", "", "", "ja_JP.SJIS", UTF8, true},
+		{"no codeset", "", "", "C", UTF8, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("LC_ALL", tt.lcAll)
+			t.Setenv("LC_CTYPE", tt.lcType)
+			t.Setenv("LANG", tt.lang)
+
+			if got := CharsetFromLocale(); got != tt.want {
+				t.Errorf("CharsetFromLocale() = %T, want %T", got, tt.want)
+			}
+			cs, err := LocaleCharset()
+			if cs != tt.want || errors.Is(err, ErrUnknownCharset) != tt.wantUnknown {
+				t.Errorf("LocaleCharset() = (%T, %v), want (%T, unknown %v)", cs, err, tt.want, tt.wantUnknown)
+			}
+		})
+	}
+}
+
+// TestRegisterCharset validates that registered charsets are found by
+// locale detection under any spelling of their name.
+func TestRegisterCharset(t *testing.T) {
+	RegisterCharset(Latin1, "Test_Charset-1")
+
+	if cs, ok := LookupCharset("testcharset1"); !ok || cs != Latin1 {
+		t.Errorf("LookupCharset() = (%T, %v), want (Latin1, true)", cs, ok)
+	}
+
+	t.Setenv("LC_ALL", "xx_XX.TEST-CHARSET-1")
+	if got := CharsetFromLocale(); got != Latin1 {
+		t.Errorf("CharsetFromLocale() = %T, want Latin1", got)
+	}
+}
@@ -0,0 +1,14 @@
+package input
+
+import "time"
+
+// Clock provides the current time for event timestamps.
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc adapts an ordinary function to the Clock interface.
+type ClockFunc func() time.Time
+
+// Now returns f().
+func (f ClockFunc) Now() time.Time { return f() }
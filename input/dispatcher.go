@@ -0,0 +1,174 @@
+package input
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Combo is a key together with the exact set of modifiers held with it.
+type Combo struct {
+	Key       Key
+	Modifiers Modifier
+}
+
+// comboOf returns the combo of k with mods held. Terminals report Ctrl+S
+// as KeyCtrlS, with or without ModCtrl, so the Ctrl letter keys are stored
+// as the letter with ModCtrl: KeyS with ModCtrl and KeyCtrlS name the same
+// combo.
+func comboOf(k Key, mods Modifier) Combo {
+	if k >= KeyCtrlA && k <= KeyCtrlZ {
+		k = KeyA + (k - KeyCtrlA)
+		mods |= ModCtrl
+	}
+	return Combo{Key: k, Modifiers: mods}
+}
+
+// Match reports which kind of handler consumed an event in
+// Dispatcher.Dispatch.
+type Match int
+
+const (
+	// MatchNone means no handler consumed the event.
+	MatchNone Match = iota
+
+	// MatchKey means a handler registered with OnKey consumed the event.
+	MatchKey
+
+	// MatchRune means the OnRune fallback consumed the event.
+	MatchRune
+
+	// MatchUnknown means the OnUnknown fallback consumed the event.
+	MatchUnknown
+)
+
+// String returns a human-readable name for the match.
+func (m Match) String() string {
+	switch m {
+	case MatchNone:
+		return "None"
+	case MatchKey:
+		return "Key"
+	case MatchRune:
+		return "Rune"
+	case MatchUnknown:
+		return "Unknown"
+	default:
+		return "Invalid"
+	}
+}
+
+// Dispatcher routes key presses from an Input to handlers registered for
+// key combos, replacing a switch over Key and Modifiers in the event loop.
+// An event is offered to, in order:
+//
+//  1. the OnKey handler for its exact Key and Modifiers;
+//  2. the OnRune fallback, if the event carries a character;
+//  3. the OnUnknown fallback, if its key is KeyUnknown.
+//
+// Only key-down events are dispatched; key-ups and EventResume events are
+// not. Handlers run on the goroutine calling Dispatch or Run.
+//
+// Handlers may be registered and replaced at any time, including from
+// within a handler.
+type Dispatcher struct {
+	input Input
+
+	mu        sync.RWMutex
+	keys      map[Combo]func(Event)
+	onRune    func(Event)
+	onUnknown func(Event)
+}
+
+// NewDispatcher creates a Dispatcher reading from in.
+func NewDispatcher(in Input) *Dispatcher {
+	return &Dispatcher{
+		input: in,
+		keys:  make(map[Combo]func(Event)),
+	}
+}
+
+// OnKey registers h for presses of k with exactly mods held, replacing
+// any handler already registered for that combo. A nil h removes it.
+// Ctrl+letter combos can be given either way: OnKey(KeyS, ModCtrl, h) and
+// OnKey(KeyCtrlS, ModNone, h) both handle the KeyCtrlS events terminals
+// send.
+func (d *Dispatcher) OnKey(k Key, mods Modifier, h func(Event)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	combo := comboOf(k, mods)
+	if h == nil {
+		delete(d.keys, combo)
+		return
+	}
+	d.keys[combo] = h
+}
+
+// OnRune registers the fallback for character input that no OnKey handler
+// consumed, such as typed text. A nil h removes it.
+func (d *Dispatcher) OnRune(h func(Event)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.onRune = h
+}
+
+// OnUnknown registers the fallback for unrecognized keys (KeyUnknown)
+// without a character. A nil h removes it.
+func (d *Dispatcher) OnUnknown(h func(Event)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.onUnknown = h
+}
+
+// Dispatch routes one event to its handler and reports which kind of
+// handler consumed it.
+func (d *Dispatcher) Dispatch(event Event) Match {
+	if event.Type != EventKey || !event.Pressed {
+		return MatchNone
+	}
+
+	h, match := d.route(event)
+	if h == nil {
+		return MatchNone
+	}
+	h(event)
+	return match
+}
+
+// route returns the handler for event. The lock is released before the
+// handler runs, so handlers may register others.
+func (d *Dispatcher) route(event Event) (func(Event), Match) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if h, ok := d.keys[comboOf(event.Key, event.Modifiers)]; ok {
+		return h, MatchKey
+	}
+	if event.Rune != 0 && d.onRune != nil {
+		return d.onRune, MatchRune
+	}
+	if event.Key == KeyUnknown && d.onUnknown != nil {
+		return d.onUnknown, MatchUnknown
+	}
+	return nil, MatchNone
+}
+
+// Run polls the Input and dispatches every event until ctx is done or the
+// Input stops. It returns ctx.Err() if ctx ended the loop, nil if the
+// Input was stopped, and Input.Err() if capture ended on its own.
+func (d *Dispatcher) Run(ctx context.Context) error {
+	for {
+		event, err := d.input.PollContext(ctx)
+		if err != nil {
+			if errors.Is(err, ErrInputClosed) {
+				if cause := d.input.Err(); cause != ErrInputClosed {
+					return cause
+				}
+				return nil
+			}
+			return err
+		}
+		d.Dispatch(event)
+	}
+}
@@ -0,0 +1,120 @@
+package input
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestDispatch validates routing of events to combo handlers and the rune
+// and unknown fallbacks.
+func TestDispatch(t *testing.T) {
+	var got string
+	d := NewDispatcher(nil)
+	d.OnKey(KeyS, ModCtrl, func(Event) { got = "save" })
+	d.OnKey(KeyQ, ModNone, func(Event) { got = "quit" })
+	d.OnRune(func(e Event) { got = "rune " + string(e.Rune) })
+	d.OnUnknown(func(Event) { got = "unknown" })
+
+	tests := []struct {
+		name      string
+		event     Event
+		wantMatch Match
+		want      string
+	}{
+		{"combo", Event{Key: KeyCtrlS, Modifiers: ModCtrl, Pressed: true}, MatchKey, "save"},
+		{"combo without modifier", Event{Key: KeyCtrlS, Pressed: true}, MatchKey, "save"},
+		{"plain key", Event{Key: KeyQ, Rune: 'q', Pressed: true}, MatchKey, "quit"},
+		{"modifiers must match", Event{Key: KeyQ, Rune: 'Q', Modifiers: ModShift, Pressed: true}, MatchRune, "rune Q"},
+		{"rune fallback", Event{Key: KeyA, Rune: 'a', Pressed: true}, MatchRune, "rune a"},
+		{"unknown fallback", Event{Key: KeyUnknown, Pressed: true}, MatchUnknown, "unknown"},
+		{"unhandled", Event{Key: KeyF5, Pressed: true}, MatchNone, ""},
+		{"key-up ignored", Event{Key: KeyQ}, MatchNone, ""},
+		{"resume ignored", Event{Type: EventResume, Pressed: true}, MatchNone, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = ""
+			if m := d.Dispatch(tt.event); m != tt.wantMatch {
+				t.Errorf("Dispatch() = %v, want %v", m, tt.wantMatch)
+			}
+			if got != tt.want {
+				t.Errorf("handler ran %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// A nil handler removes the registration
+	d.OnKey(KeyS, ModCtrl, nil)
+	if m := d.Dispatch(Event{Key: KeyCtrlS, Modifiers: ModCtrl, Pressed: true}); m != MatchNone {
+		t.Errorf("Dispatch() after removal = %v, want None", m)
+	}
+}
+
+// TestDispatchParsed validates that combos registered as a letter with
+// ModCtrl match the Ctrl keys terminals actually send.
+func TestDispatchParsed(t *testing.T) {
+	tests := []struct {
+		name string
+		seq  string
+	}{
+		{"control byte", "\x13"},
+		{"win32 input", "\x1b[83;31;19;1;8;1_"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var saved bool
+			d := NewDispatcher(nil)
+			d.OnKey(KeyS, ModCtrl, func(Event) { saved = true })
+
+			event, err := NewSequenceParser().Parse([]byte(tt.seq))
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.seq, err)
+			}
+			if m := d.Dispatch(event); m != MatchKey || !saved {
+				t.Errorf("Dispatch(%+v) = %v, want Key", event, m)
+			}
+		})
+	}
+}
+
+// TestDispatcherRun validates that Run dispatches polled events and ends
+// on context cancellation and on Stop.
+func TestDispatcherRun(t *testing.T) {
+	backend := newFakeBackend()
+	in := newTestInput(backend)
+	if err := in.Start(); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	d := NewDispatcher(in)
+	d.OnKey(KeyEscape, ModNone, func(Event) { cancel() })
+
+	result := make(chan error, 1)
+	go func() { result <- d.Run(ctx) }()
+	backend.events <- Event{Key: KeyEscape, Pressed: true}
+
+	select {
+	case err := <-result:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Run() after cancel = %v, want context.Canceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Run() did not return after cancel")
+	}
+
+	go func() { result <- d.Run(context.Background()) }()
+	in.Stop()
+	select {
+	case err := <-result:
+		if err != nil {
+			t.Errorf("Run() after Stop = %v, want nil", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Run() did not return after Stop")
+	}
+}
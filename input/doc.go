@@ -33,11 +33,29 @@
 //
 //   - Normalized key codes across all platforms (KeyUp, KeyDown, KeyA, etc.)
 //   - Blocking (Poll) and non-blocking (Next) event retrieval
+//   - Channel (Events) and range-over-func iterator (All) accessors
+//   - Allocation-free batched draining for game loops (DrainInto, NextInto)
+//   - Cancellable blocking with deadlines (PollContext, PollTimeout)
 //   - Real-time key state queries (IsPressed)
+//   - Broadcast subscriptions for several consumers (Subscribe)
+//   - Middleware to remap, drop or expand events (Use, UseExpander)
+//   - Key-combo handler routing (Dispatcher)
 //   - Modifier key detection (Shift, Alt, Ctrl)
-//   - Autorepeat event flagging
+//   - Autorepeat event flagging, detected from press timing where the
+//     terminal does not report it (WithRepeatTiming)
+//   - Key-release inference for terminals without key-ups (WithReleaseInference)
 //   - Monotonic event timestamps
-//   - Graceful terminal restoration
+//   - Typed errors and capture-end reporting (Err, Done, ErrAlreadyStarted,
+//     ErrTooManyErrors)
+//   - Graceful terminal restoration, including on signals and panics
+//     (WithSignalGuard, RestoreOnPanic)
+//   - Keyboard input from /dev/tty when stdin is a pipe (WithTTYPath)
+//   - Shell job control: suspend on Ctrl+Z, resume on fg (WithJobControl)
+//   - Handing the terminal to a subprocess such as $EDITOR (Release)
+//   - Optional grapheme-cluster text events (WithGraphemeClusters)
+//   - 8-bit C1 control and high-bit Meta input (WithC1Controls, WithHighBitMeta)
+//   - Legacy text encodings such as Latin-1 (WithCharset, WithLocaleCharset)
+//   - Raw terminal bytes on events for debugging (Event.Raw, WithRawBytes)
 //
 // # Platform Support
 //
@@ -49,6 +67,23 @@
 // All backends normalize escape sequences and key codes to produce identical
 // Event values, ensuring cross-platform compatibility.
 //
+// Terminal input is split into sequences by an ECMA-48 state machine that
+// knows where every well-formed sequence ends, recognized or not. Unknown
+// sequences are reported as KeyUnknown, terminal replies (OSC, DCS and other
+// control strings) are dropped, and malformed or truncated input never
+// desynchronizes the stream: the next ESC always starts a fresh sequence.
+//
+// # Options
+//
+// New accepts functional options that tune the input system; New() with no
+// arguments keeps the defaults:
+//
+//	in := input.New(
+//	    input.WithBufferSize(256),
+//	    input.WithEscapeTimeout(100*time.Millisecond),
+//	    input.WithProtocols(input.ProtocolWin32Input),
+//	)
+//
 // # Thread Safety
 //
 // All Input methods are safe for concurrent use. The input capture runs in
@@ -89,5 +124,16 @@
 //   - Alternative key schemes (multiple keys per action)
 //   - Action-based game logic (decoupled from physical keys)
 //
+// For edge-triggered logic, call Update once per frame and query the
+// returned snapshot. Each Update consumes the queued events:
+//
+//	frame := game.Update()
+//	if frame.ActionJustPressed("jump") {
+//	    player.Jump() // once per press, not every frame it is held
+//	}
+//	if frame.ActionHeldDuration("fire") > time.Second {
+//	    player.ChargeShot()
+//	}
+//
 // Performance: ~9ns per IsActionPressed call, zero allocations, <1ms response time.
 package input
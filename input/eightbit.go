@@ -0,0 +1,57 @@
+package input
+
+// 8-bit C1 control introducers equivalent to ESC [ and ESC O.
+const (
+	c1SS3 = 0x8f
+	c1CSI = 0x9b
+)
+
+// isC1 reports whether b is decoded as a C1 control. With high-bit Meta
+// also enabled only the CSI and SS3 introducers are; every other byte is a
+// Meta key.
+func (p *SequenceParser) isC1(b byte) bool {
+	if !p.cfg.c1Controls || b < 0x80 || b > 0x9f {
+		return false
+	}
+	return !p.cfg.highBitMeta || b == c1CSI || b == c1SS3
+}
+
+// parseEightBit decodes sequences that start with a byte >= 0x80 under the
+// C1 control and high-bit Meta input modes. ok is false when neither mode
+// applies to seq and it should be decoded as UTF-8.
+func (p *SequenceParser) parseEightBit(seq []byte) (event Event, ok bool, err error) {
+	b := seq[0]
+
+	if p.cfg.c1Controls && (b == c1CSI || b == c1SS3) && len(seq) <= maxSequenceLength {
+		// Rewrite the C1 introducer as its 7-bit equivalent and decode that
+		var buf [maxSequenceLength + 1]byte
+		buf[0] = 0x1b
+		buf[1] = '['
+		if b == c1SS3 {
+			buf[1] = 'O'
+		}
+		n := copy(buf[2:], seq[1:])
+		event, err = p.Parse(buf[:n+2])
+		return event, true, err
+	}
+
+	if p.isC1(b) && (b == c1OSC || b == c1DCS || b == c1SOS || b == c1PM || b == c1APC) && len(seq) > 1 {
+		// Control strings are terminal replies, not keys
+		return Event{}, true, errNoEvent
+	}
+
+	if p.isC1(b) {
+		// Other C1 controls have no key meaning
+		return p.unknownEvent(), true, nil
+	}
+
+	if p.cfg.highBitMeta && len(seq) == 1 {
+		// Meta sets the high bit: decode the 7-bit key and add Alt
+		key := [1]byte{b &^ 0x80}
+		event, err = p.Parse(key[:])
+		event.Modifiers |= ModAlt
+		return event, true, err
+	}
+
+	return Event{}, false, nil
+}
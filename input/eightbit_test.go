@@ -0,0 +1,56 @@
+package input
+
+import "testing"
+
+// TestEightBitInput validates decoding of C1 control introducers and
+// high-bit Meta bytes in the modes that enable them.
+func TestEightBitInput(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []Option
+		seq      string
+		wantKey  Key
+		wantRune rune
+		wantMods Modifier
+	}{
+		{"C1 CSI arrow", []Option{WithC1Controls()}, "\x9bA", KeyUp, 0, ModNone},
+		{"C1 CSI tilde", []Option{WithC1Controls()}, "\x9b3~", KeyDelete, 0, ModNone},
+		{"C1 SS3 F1", []Option{WithC1Controls()}, "\x8fP", KeyF1, 0, ModNone},
+		{"C1 other", []Option{WithC1Controls()}, "\x85", KeyUnknown, 0, ModNone},
+		{"C1 keeps UTF-8", []Option{WithC1Controls()}, "é", KeyUnknown, 'é', ModNone},
+		{"Meta letter", []Option{WithHighBitMeta()}, "\xe1", KeyA, 'a', ModAlt},
+		{"Meta digit", []Option{WithHighBitMeta()}, "\xb1", Key1, '1', ModAlt},
+		{"Meta Ctrl+C", []Option{WithHighBitMeta()}, "\x83", KeyCtrlC, 0, ModCtrl | ModAlt},
+		{"Meta Escape", []Option{WithHighBitMeta()}, "\x9b", KeyEscape, 0, ModAlt},
+		{"C1 wins over Meta", []Option{WithC1Controls(), WithHighBitMeta()}, "\x9bB", KeyDown, 0, ModNone},
+		{"Meta over other C1", []Option{WithC1Controls(), WithHighBitMeta()}, "\x83", KeyCtrlC, 0, ModCtrl | ModAlt},
+		{"Meta over C1 DCS", []Option{WithC1Controls(), WithHighBitMeta()}, "\x90", KeyCtrlP, 0, ModCtrl | ModAlt},
+		{"default is UTF-8", nil, "\xc3\xa9", KeyUnknown, 'é', ModNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewSequenceParser(tt.opts...)
+			buf := []byte(tt.seq)
+
+			n, complete := p.split(buf)
+			if !complete || n != len(buf) {
+				t.Fatalf("split(%q) = (%d, %v), want (%d, true)", tt.seq, n, complete, len(buf))
+			}
+
+			event, err := p.Parse(buf)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if event.Key != tt.wantKey {
+				t.Errorf("Key = %v, want %v", event.Key, tt.wantKey)
+			}
+			if event.Rune != tt.wantRune {
+				t.Errorf("Rune = %q, want %q", event.Rune, tt.wantRune)
+			}
+			if event.Modifiers != tt.wantMods {
+				t.Errorf("Modifiers = %v, want %v", event.Modifiers, tt.wantMods)
+			}
+		})
+	}
+}
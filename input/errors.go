@@ -0,0 +1,49 @@
+package input
+
+import "errors"
+
+// ErrInputClosed is returned by PollContext and PollTimeout when the input
+// system has been stopped or capture has ended. Input.Err returns it after
+// Stop, and wraps it with io.EOF when the terminal was closed, as on an
+// SSH hangup.
+var ErrInputClosed = errors.New("input: closed")
+
+// ErrAlreadyStarted is returned by Start when the input system is running.
+var ErrAlreadyStarted = errors.New("input: already started")
+
+// ErrTooManyErrors is matched (with errors.Is) by Input.Err when capture
+// gave up after repeated read errors. The error also wraps the last read
+// error.
+var ErrTooManyErrors = errors.New("input: too many consecutive read errors")
+
+// ErrUnknownCharset is matched (with errors.Is) by the error LocaleCharset
+// returns when the locale names a codeset that is not registered.
+var ErrUnknownCharset = errors.New("input: unknown charset")
+
+// ErrNotTerminal is matched (with errors.Is) by the error Start returns when
+// no terminal is available for keyboard input: stdin is not a terminal and
+// there is no controlling terminal to fall back to.
+var ErrNotTerminal = errors.New("input: not a terminal")
+
+// TerminalError reports that a file could not be used as a terminal.
+// It matches ErrNotTerminal with errors.Is and unwraps to the underlying
+// system error.
+type TerminalError struct {
+	// Path is the name of the file that was tried, e.g. "/dev/stdin" or
+	// "/dev/tty".
+	Path string
+
+	// Err is the error from opening the file or querying its terminal
+	// state.
+	Err error
+}
+
+func (e *TerminalError) Error() string {
+	return "input: " + e.Path + " is not a terminal: " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *TerminalError) Unwrap() error { return e.Err }
+
+// Is reports whether target is ErrNotTerminal.
+func (e *TerminalError) Is(target error) bool { return target == ErrNotTerminal }
@@ -0,0 +1,29 @@
+package input
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+	"testing"
+)
+
+// TestTerminalError validates that TerminalError matches ErrNotTerminal,
+// unwraps to the system error and survives wrapping.
+func TestTerminalError(t *testing.T) {
+	err := fmt.Errorf("failed to initialize backend: %w",
+		&TerminalError{Path: "/dev/tty", Err: syscall.ENXIO})
+
+	if !errors.Is(err, ErrNotTerminal) {
+		t.Error("errors.Is(err, ErrNotTerminal) = false, want true")
+	}
+	if !errors.Is(err, syscall.ENXIO) {
+		t.Error("errors.Is(err, ENXIO) = false, want true")
+	}
+	var termErr *TerminalError
+	if !errors.As(err, &termErr) || termErr.Path != "/dev/tty" {
+		t.Errorf("errors.As() = %v, want TerminalError for /dev/tty", termErr)
+	}
+	if errors.Is(err, ErrInputClosed) {
+		t.Error("errors.Is(err, ErrInputClosed) = true, want false")
+	}
+}
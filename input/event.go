@@ -215,9 +215,39 @@ const (
 	ModCtrl
 )
 
+// EventType distinguishes key events from notifications about the input
+// session itself.
+type EventType int
+
+const (
+	// EventKey is a keyboard event. It is the zero value, so events built
+	// without a Type are key events.
+	EventKey EventType = iota
+
+	// EventResume reports that the process was continued after being
+	// suspended with Ctrl+Z (see WithJobControl). The terminal is back in
+	// raw mode; applications should redraw. Key is KeyUnknown.
+	EventResume
+)
+
+// String returns the name of the event type.
+func (t EventType) String() string {
+	switch t {
+	case EventKey:
+		return "Key"
+	case EventResume:
+		return "Resume"
+	default:
+		return "Unknown"
+	}
+}
+
 // Event represents a single keyboard event with all associated metadata.
 // Events are produced by the input system and consumed via Poll or Next.
 type Event struct {
+	// Type is the kind of event. It is EventKey for keyboard events.
+	Type EventType
+
 	// Key is the normalized key code for this event.
 	Key Key
 
@@ -225,6 +255,12 @@ type Event struct {
 	// For non-printable keys (arrows, function keys, etc.), Rune is 0.
 	Rune rune
 
+	// Text is the complete grapheme cluster for text input when grapheme
+	// clustering is enabled (see WithGraphemeClusters). For clusters made
+	// of several code points, Rune holds only the first one.
+	// Text is empty when clustering is disabled and for non-text keys.
+	Text string
+
 	// Modifiers contains the active modifier keys (Shift, Alt, Ctrl).
 	// Multiple modifiers can be combined using bitwise OR.
 	Modifiers Modifier
@@ -240,10 +276,26 @@ type Event struct {
 
 	// Repeat indicates whether this is an OS autorepeat event.
 	// The first press has Repeat=false, subsequent repeats have Repeat=true.
+	// Terminals that report repeats as ordinary presses have them detected
+	// from their timing (see WithRepeatTiming).
 	Repeat bool
+
+	// Synthetic is true for key-up events inferred by the input system
+	// rather than reported by the terminal (see WithReleaseInference).
+	Synthetic bool
+
+	// Raw holds the exact bytes the terminal sent for this event, for
+	// diagnosing keys that are decoded wrongly. It is set on every event
+	// when WithRawBytes is given, and always on unrecognized input
+	// (KeyUnknown without a Rune, or with utf8.RuneError for bytes that
+	// are not valid UTF-8); otherwise it is empty, so decoding does not
+	// allocate. Synthesized events have no Raw bytes. A string keeps
+	// Event comparable with ==.
+	Raw string
 }
 
 // String returns a human-readable string representation of the Key.
+//
 //nolint:cyclop // Unavoidable complexity for 100+ key mappings
 func (k Key) String() string {
 	switch k {
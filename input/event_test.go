@@ -210,3 +210,22 @@ func TestModifierCombinations(t *testing.T) {
 		t.Error("All modifiers should include Ctrl")
 	}
 }
+
+// TestEventComparable validates that events, including their raw bytes,
+// can be compared with == and used as map keys.
+func TestEventComparable(t *testing.T) {
+	a := Event{Key: KeyUnknown, Pressed: true, Raw: "\x1b[5q"}
+	b := Event{Key: KeyUnknown, Pressed: true, Raw: "\x1b[5q"}
+	c := Event{Key: KeyUnknown, Pressed: true, Raw: "\x1b[6q"}
+
+	if a != b {
+		t.Error("events with the same fields are not equal")
+	}
+	if a == c {
+		t.Error("events with different Raw bytes are equal")
+	}
+	seen := map[Event]int{a: 1}
+	if seen[b] != 1 {
+		t.Error("equal event not found as map key")
+	}
+}
@@ -0,0 +1,108 @@
+package input
+
+import (
+	"io"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeBackend is a Backend fed from a channel, for testing inputImpl
+// without a terminal. After close, ReadEvent returns io.EOF so the capture
+// goroutine exits and Stop does not block; the next Init reopens it.
+type fakeBackend struct {
+	events chan Event
+
+	mu       sync.Mutex
+	closed   chan struct{}
+	inits    int
+	restores int
+	resets   int
+
+	// initErr, if set, is returned by Init; readErr by ReadEvent.
+	initErr error
+	readErr error
+}
+
+func newFakeBackend() *fakeBackend {
+	return &fakeBackend{
+		events: make(chan Event, 16),
+		closed: make(chan struct{}),
+	}
+}
+
+func (b *fakeBackend) Init() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.inits++
+	if b.initErr != nil {
+		return b.initErr
+	}
+	select {
+	case <-b.closed:
+		b.closed = make(chan struct{})
+	default:
+	}
+	return nil
+}
+
+func (b *fakeBackend) Restore() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.restores++
+	return nil
+}
+
+func (b *fakeBackend) resetTerminal() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.resets++
+}
+
+func (b *fakeBackend) ReadEvent() (Event, error) {
+	b.mu.Lock()
+	closed, readErr := b.closed, b.readErr
+	b.mu.Unlock()
+
+	if readErr != nil {
+		return Event{}, readErr
+	}
+
+	select {
+	case event := <-b.events:
+		return event, nil
+	case <-closed:
+		return Event{}, io.EOF
+	}
+}
+
+func (b *fakeBackend) Cancel() { b.close() }
+
+func (b *fakeBackend) close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	select {
+	case <-b.closed:
+	default:
+		close(b.closed)
+	}
+}
+
+// newTestInput returns an unstarted inputImpl reading from backend.
+func newTestInput(backend Backend) *inputImpl {
+	in := New().(*inputImpl)
+	in.backend = backend
+	return in
+}
+
+// waitQueued waits until n events are queued on in.
+func waitQueued(t *testing.T, in *inputImpl, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for len(in.events) < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %d queued events", n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
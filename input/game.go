@@ -14,6 +14,18 @@ type GameInput interface {
 	// Delegates to the wrapped Input.Stop().
 	Stop()
 
+	// Release temporarily gives the terminal back to run fn.
+	// Delegates to the wrapped Input.Release(); bindings are kept.
+	Release(fn func() error) error
+
+	// Update advances the wrapped Input to a new frame (see Input.Update)
+	// and returns it with the current bindings, for action-level edge
+	// queries such as ActionJustPressed.
+	Update() GameSnapshot
+
+	// Snapshot returns the frame taken by the most recent Update.
+	Snapshot() GameSnapshot
+
 	// IsActionPressed returns true if any key bound to the action is currently pressed.
 	// Returns false if action has no bound keys or none are pressed.
 	//
@@ -1,6 +1,9 @@
 package input
 
-import "sync"
+import (
+	"maps"
+	"sync"
+)
 
 // gameInputImpl is the concrete implementation of GameInput.
 type gameInputImpl struct {
@@ -19,6 +22,11 @@ func (g *gameInputImpl) Stop() {
 	g.input.Stop()
 }
 
+// Release delegates to the underlying Input.
+func (g *gameInputImpl) Release(fn func() error) error {
+	return g.input.Release(fn)
+}
+
 // IsActionPressed returns true if any key bound to the action is pressed.
 func (g *gameInputImpl) IsActionPressed(action string) bool {
 	g.mu.RLock()
@@ -43,16 +51,37 @@ func (g *gameInputImpl) IsActionPressed(action string) bool {
 }
 
 // Bind associates keys with an action. Empty keys unbinds the action.
+// The bindings map is replaced rather than modified, so snapshots can
+// share it.
 func (g *gameInputImpl) Bind(action string, keys ...Key) {
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
+	bindings := maps.Clone(g.bindings)
 	if len(keys) == 0 {
-		delete(g.bindings, action) // Unbind
+		delete(bindings, action) // Unbind
 	} else {
 		// Defensive copy to prevent aliasing issues
 		keyCopy := make([]Key, len(keys))
 		copy(keyCopy, keys)
-		g.bindings[action] = keyCopy // Bind/rebind
+		bindings[action] = keyCopy // Bind/rebind
 	}
+	g.bindings = bindings
+}
+
+// Update advances the underlying Input to a new frame.
+func (g *gameInputImpl) Update() GameSnapshot {
+	return g.snapshot(g.input.Update())
+}
+
+// Snapshot returns the frame taken by the last Update.
+func (g *gameInputImpl) Snapshot() GameSnapshot {
+	return g.snapshot(g.input.Snapshot())
+}
+
+// snapshot pairs s with the current bindings.
+func (g *gameInputImpl) snapshot(s Snapshot) GameSnapshot {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return GameSnapshot{Snapshot: s, bindings: g.bindings}
 }
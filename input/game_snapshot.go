@@ -0,0 +1,60 @@
+package input
+
+import "time"
+
+// GameSnapshot is a Snapshot with the action bindings in effect when it was
+// taken, returned by GameInput.Update. Key-level queries come from the
+// embedded Snapshot; the Action methods apply the same OR logic as
+// GameInput.IsActionPressed.
+type GameSnapshot struct {
+	Snapshot
+
+	// bindings is never modified after the snapshot is taken; Bind
+	// replaces the map instead.
+	bindings map[string][]Key
+}
+
+// IsActionPressed reports whether any key bound to action was held at the
+// end of the frame.
+func (s GameSnapshot) IsActionPressed(action string) bool {
+	for _, k := range s.bindings[action] {
+		if s.IsPressed(k) {
+			return true
+		}
+	}
+	return false
+}
+
+// ActionJustPressed reports whether a key bound to action went down during
+// the frame.
+func (s GameSnapshot) ActionJustPressed(action string) bool {
+	for _, k := range s.bindings[action] {
+		if s.JustPressed(k) {
+			return true
+		}
+	}
+	return false
+}
+
+// ActionJustReleased reports whether a key bound to action went up during
+// the frame and no bound key is still held, so the action as a whole ended.
+func (s GameSnapshot) ActionJustReleased(action string) bool {
+	released := false
+	for _, k := range s.bindings[action] {
+		if s.IsPressed(k) {
+			return false
+		}
+		released = released || s.JustReleased(k)
+	}
+	return released
+}
+
+// ActionHeldDuration returns how long the action had been held at the end
+// of the frame: the longest HeldDuration of its bound keys.
+func (s GameSnapshot) ActionHeldDuration(action string) time.Duration {
+	var longest time.Duration
+	for _, k := range s.bindings[action] {
+		longest = max(longest, s.HeldDuration(k))
+	}
+	return longest
+}
@@ -0,0 +1,271 @@
+package input
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// graphemeProp is the Grapheme_Cluster_Break property of a rune,
+// as defined by Unicode UAX #29.
+type graphemeProp uint8
+
+const (
+	gbOther graphemeProp = iota
+	gbCR
+	gbLF
+	gbControl
+	gbExtend
+	gbZWJ
+	gbRegionalIndicator
+	gbPrepend
+	gbSpacingMark
+	gbL
+	gbV
+	gbT
+	gbLV
+	gbLVT
+)
+
+// Hangul syllable block layout, used to tell LV from LVT syllables.
+const (
+	hangulSBase  = 0xac00
+	hangulSCount = 11172
+	hangulTCount = 28
+)
+
+// graphemeExtendExtra lists Extend code points that are not nonspacing or
+// enclosing marks: ZWNJ, halfwidth voiced sound marks, emoji modifiers and
+// tag characters.
+var graphemeExtendExtra = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x200c, Hi: 0x200c, Stride: 1},
+		{Lo: 0xff9e, Hi: 0xff9f, Stride: 1},
+	},
+	R32: []unicode.Range32{
+		{Lo: 0x1f3fb, Hi: 0x1f3ff, Stride: 1},
+		{Lo: 0xe0020, Hi: 0xe007f, Stride: 1},
+	},
+}
+
+// graphemePrepend lists the Prepend code points in common use.
+var graphemePrepend = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x0600, Hi: 0x0605, Stride: 1},
+		{Lo: 0x06dd, Hi: 0x06dd, Stride: 1},
+		{Lo: 0x070f, Hi: 0x070f, Stride: 1},
+		{Lo: 0x08e2, Hi: 0x08e2, Stride: 1},
+		{Lo: 0x0d4e, Hi: 0x0d4e, Stride: 1},
+	},
+	R32: []unicode.Range32{
+		{Lo: 0x110bd, Hi: 0x110bd, Stride: 1},
+		{Lo: 0x110cd, Hi: 0x110cd, Stride: 1},
+	},
+}
+
+// extendedPictographic approximates the Extended_Pictographic property
+// closely enough to keep emoji ZWJ sequences together.
+var extendedPictographic = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x00a9, Hi: 0x00a9, Stride: 1},
+		{Lo: 0x00ae, Hi: 0x00ae, Stride: 1},
+		{Lo: 0x203c, Hi: 0x203c, Stride: 1},
+		{Lo: 0x2049, Hi: 0x2049, Stride: 1},
+		{Lo: 0x2122, Hi: 0x2122, Stride: 1},
+		{Lo: 0x2139, Hi: 0x2139, Stride: 1},
+		{Lo: 0x2194, Hi: 0x2199, Stride: 1},
+		{Lo: 0x21a9, Hi: 0x21aa, Stride: 1},
+		{Lo: 0x231a, Hi: 0x231b, Stride: 1},
+		{Lo: 0x2328, Hi: 0x2328, Stride: 1},
+		{Lo: 0x2388, Hi: 0x2388, Stride: 1},
+		{Lo: 0x23cf, Hi: 0x23cf, Stride: 1},
+		{Lo: 0x23e9, Hi: 0x23f3, Stride: 1},
+		{Lo: 0x23f8, Hi: 0x23fa, Stride: 1},
+		{Lo: 0x24c2, Hi: 0x24c2, Stride: 1},
+		{Lo: 0x25aa, Hi: 0x25ab, Stride: 1},
+		{Lo: 0x25b6, Hi: 0x25b6, Stride: 1},
+		{Lo: 0x25c0, Hi: 0x25c0, Stride: 1},
+		{Lo: 0x25fb, Hi: 0x25fe, Stride: 1},
+		{Lo: 0x2600, Hi: 0x27bf, Stride: 1},
+		{Lo: 0x2934, Hi: 0x2935, Stride: 1},
+		{Lo: 0x2b05, Hi: 0x2b07, Stride: 1},
+		{Lo: 0x2b1b, Hi: 0x2b1c, Stride: 1},
+		{Lo: 0x2b50, Hi: 0x2b50, Stride: 1},
+		{Lo: 0x2b55, Hi: 0x2b55, Stride: 1},
+		{Lo: 0x3030, Hi: 0x3030, Stride: 1},
+		{Lo: 0x303d, Hi: 0x303d, Stride: 1},
+		{Lo: 0x3297, Hi: 0x3297, Stride: 1},
+		{Lo: 0x3299, Hi: 0x3299, Stride: 1},
+	},
+	R32: []unicode.Range32{
+		{Lo: 0x1f000, Hi: 0x1f0ff, Stride: 1},
+		{Lo: 0x1f10d, Hi: 0x1f10f, Stride: 1},
+		{Lo: 0x1f12f, Hi: 0x1f12f, Stride: 1},
+		{Lo: 0x1f16c, Hi: 0x1f171, Stride: 1},
+		{Lo: 0x1f17e, Hi: 0x1f17f, Stride: 1},
+		{Lo: 0x1f18e, Hi: 0x1f18e, Stride: 1},
+		{Lo: 0x1f191, Hi: 0x1f19a, Stride: 1},
+		{Lo: 0x1f1ad, Hi: 0x1f1e5, Stride: 1},
+		{Lo: 0x1f201, Hi: 0x1f20f, Stride: 1},
+		{Lo: 0x1f21a, Hi: 0x1f21a, Stride: 1},
+		{Lo: 0x1f22f, Hi: 0x1f22f, Stride: 1},
+		{Lo: 0x1f232, Hi: 0x1f23a, Stride: 1},
+		{Lo: 0x1f23c, Hi: 0x1f23f, Stride: 1},
+		{Lo: 0x1f249, Hi: 0x1f3fa, Stride: 1},
+		{Lo: 0x1f400, Hi: 0x1f53d, Stride: 1},
+		{Lo: 0x1f546, Hi: 0x1f64f, Stride: 1},
+		{Lo: 0x1f680, Hi: 0x1f6ff, Stride: 1},
+		{Lo: 0x1f774, Hi: 0x1f77f, Stride: 1},
+		{Lo: 0x1f7d5, Hi: 0x1f7ff, Stride: 1},
+		{Lo: 0x1f80c, Hi: 0x1f80f, Stride: 1},
+		{Lo: 0x1f848, Hi: 0x1f84f, Stride: 1},
+		{Lo: 0x1f85a, Hi: 0x1f85f, Stride: 1},
+		{Lo: 0x1f888, Hi: 0x1f88f, Stride: 1},
+		{Lo: 0x1f8ae, Hi: 0x1f8ff, Stride: 1},
+		{Lo: 0x1f90c, Hi: 0x1f93a, Stride: 1},
+		{Lo: 0x1f93c, Hi: 0x1f945, Stride: 1},
+		{Lo: 0x1f947, Hi: 0x1faff, Stride: 1},
+		{Lo: 0x1fc00, Hi: 0x1fffd, Stride: 1},
+	},
+}
+
+// graphemeProperty returns the Grapheme_Cluster_Break property of r.
+func graphemeProperty(r rune) graphemeProp {
+	switch {
+	case r < 0x20 || r == 0x7f:
+		switch r {
+		case '\r':
+			return gbCR
+		case '\n':
+			return gbLF
+		}
+		return gbControl
+	case r >= 0x80 && r < 0xa0, r == 0xad:
+		return gbControl
+	case r < 0x300:
+		// Fast path: Latin-1 and Latin Extended contain no marks
+		return gbOther
+	case r == 0x200d:
+		return gbZWJ
+	case r >= 0x1f1e6 && r <= 0x1f1ff:
+		return gbRegionalIndicator
+	case r >= 0x1100 && r <= 0x115f, r >= 0xa960 && r <= 0xa97c:
+		return gbL
+	case r >= 0x1160 && r <= 0x11a7, r >= 0xd7b0 && r <= 0xd7c6:
+		return gbV
+	case r >= 0x11a8 && r <= 0x11ff, r >= 0xd7cb && r <= 0xd7fb:
+		return gbT
+	case r >= hangulSBase && r < hangulSBase+hangulSCount:
+		if (r-hangulSBase)%hangulTCount == 0 {
+			return gbLV
+		}
+		return gbLVT
+	case unicode.Is(graphemePrepend, r):
+		return gbPrepend
+	case unicode.In(r, unicode.Mn, unicode.Me, graphemeExtendExtra):
+		return gbExtend
+	case unicode.Is(unicode.Mc, r):
+		return gbSpacingMark
+	case unicode.In(r, unicode.Cc, unicode.Cf, unicode.Zl, unicode.Zp):
+		return gbControl
+	}
+	return gbOther
+}
+
+// graphemeClusterLength returns the length in bytes of the first extended
+// grapheme cluster in buf. complete is false when buf ends at a point where
+// the cluster is expected to continue (after an emoji ZWJ, an unpaired
+// regional indicator, a Prepend character, or inside a UTF-8 sequence),
+// in which case n covers the bytes seen so far.
+func graphemeClusterLength(buf []byte) (n int, complete bool) {
+	if len(buf) == 0 {
+		return 0, false
+	}
+	if !utf8.FullRune(buf) {
+		return len(buf), false
+	}
+
+	r, size := utf8.DecodeRune(buf)
+	prev := graphemeProperty(r)
+	n = size
+
+	// State for GB11 (emoji ZWJ sequences) and GB12/GB13 (flag pairs)
+	pictographic := unicode.Is(extendedPictographic, r)
+	riCount := 0
+	if prev == gbRegionalIndicator {
+		riCount = 1
+	}
+
+	for n < len(buf) {
+		if !utf8.FullRune(buf[n:]) {
+			return len(buf), false
+		}
+		r, size = utf8.DecodeRune(buf[n:])
+		next := graphemeProperty(r)
+		nextPictographic := unicode.Is(extendedPictographic, r)
+
+		if graphemeBreak(prev, next, pictographic && nextPictographic, riCount) {
+			return n, true
+		}
+
+		// Track whether we are inside ExtPict Extend* ZWJ
+		switch {
+		case nextPictographic:
+			pictographic = true
+		case next == gbExtend || next == gbZWJ:
+			// keeps the current emoji state
+		default:
+			pictographic = false
+		}
+		if next == gbRegionalIndicator {
+			riCount++
+		} else {
+			riCount = 0
+		}
+
+		prev = next
+		n += size
+	}
+
+	// The buffer ended inside the cluster; only wait for more input when
+	// the last rune cannot stand on its own.
+	switch {
+	case prev == gbZWJ && pictographic, prev == gbPrepend:
+		return n, false
+	case prev == gbRegionalIndicator && riCount%2 == 1:
+		return n, false
+	}
+	return n, true
+}
+
+// graphemeBreak reports whether there is a cluster boundary between a rune
+// with property prev and one with property next. emojiZWJ is true when prev
+// is a ZWJ ending an Extended_Pictographic Extend* ZWJ sequence and next is
+// Extended_Pictographic. riCount is the number of consecutive regional
+// indicators ending at prev.
+func graphemeBreak(prev, next graphemeProp, emojiZWJ bool, riCount int) bool {
+	switch {
+	case prev == gbCR && next == gbLF: // GB3
+		return false
+	case prev == gbControl || prev == gbCR || prev == gbLF: // GB4
+		return true
+	case next == gbControl || next == gbCR || next == gbLF: // GB5
+		return true
+	case prev == gbL && (next == gbL || next == gbV || next == gbLV || next == gbLVT): // GB6
+		return false
+	case (prev == gbLV || prev == gbV) && (next == gbV || next == gbT): // GB7
+		return false
+	case (prev == gbLVT || prev == gbT) && next == gbT: // GB8
+		return false
+	case next == gbExtend || next == gbZWJ: // GB9
+		return false
+	case next == gbSpacingMark: // GB9a
+		return false
+	case prev == gbPrepend: // GB9b
+		return false
+	case prev == gbZWJ && emojiZWJ: // GB11
+		return false
+	case prev == gbRegionalIndicator && next == gbRegionalIndicator: // GB12, GB13
+		return riCount%2 == 0
+	}
+	return true // GB999
+}
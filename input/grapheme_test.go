@@ -0,0 +1,54 @@
+package input
+
+import "testing"
+
+// TestGraphemeClusterLength validates extended grapheme cluster boundaries
+// for the clusters terminals commonly deliver in a single write.
+func TestGraphemeClusterLength(t *testing.T) {
+	tests := []struct {
+		name         string
+		buf          string
+		wantN        int
+		wantComplete bool
+	}{
+		{"ASCII", "ab", 1, true},
+		{"single ASCII", "a", 1, true},
+		{"combining acute", "éx", 3, true},
+		{"stacked marks", "ạ́", 5, true},
+		{"precomposed", "éa", 2, true},
+		{"flag", "🇺🇸🇫🇷", 8, true},
+		{"unpaired regional indicator", "🇺", 4, false},
+		{"family ZWJ sequence", "👨‍👩‍👧x", 18, true},
+		{"ZWJ awaiting emoji", "👨‍", 7, false},
+		{"skin tone modifier", "👍🏽!", 8, true},
+		{"variation selector", "❤️!", 6, true},
+		{"Hangul jamo", "각가", 9, true},
+		{"Hangul syllables", "한국", 3, true},
+		{"spacing mark", "का", 6, true},
+		{"control breaks", "a\x1b", 1, true},
+		{"partial UTF-8", "e\xcc", 2, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, complete := graphemeClusterLength([]byte(tt.buf))
+			if n != tt.wantN || complete != tt.wantComplete {
+				t.Errorf("graphemeClusterLength(%q) = (%d, %v), want (%d, %v)",
+					tt.buf, n, complete, tt.wantN, tt.wantComplete)
+			}
+		})
+	}
+}
+
+// TestGraphemeClusterSplit validates that the parser only groups clusters
+// when grapheme clustering is enabled.
+func TestGraphemeClusterSplit(t *testing.T) {
+	buf := []byte("é")
+
+	if n, _ := NewSequenceParser().split(buf); n != 1 {
+		t.Errorf("split without clustering = %d, want 1", n)
+	}
+	if n, _ := NewSequenceParser(WithGraphemeClusters()).split(buf); n != 3 {
+		t.Errorf("split with clustering = %d, want 3", n)
+	}
+}
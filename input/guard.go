@@ -0,0 +1,135 @@
+package input
+
+import (
+	"maps"
+	"os"
+	"os/signal"
+	"slices"
+	"sync"
+	"syscall"
+)
+
+// guardSignals are the signals WithSignalGuard intercepts. Each terminates
+// the process by default, which would leave the terminal in raw mode.
+var guardSignals = []os.Signal{
+	syscall.SIGINT,
+	syscall.SIGTERM,
+	syscall.SIGHUP,
+	syscall.SIGQUIT,
+}
+
+// terminalResetSequence turns off terminal modes an application may have
+// enabled on its own: mouse reporting, SGR mouse, bracketed paste and the
+// kitty keyboard protocol. Terminals ignore the ones they do not know.
+const terminalResetSequence = "\x1b[?1000l\x1b[?1002l\x1b[?1003l\x1b[?1006l" +
+	"\x1b[?2004l\x1b[<99u"
+
+// terminalResetter is implemented by backends that can put the terminal
+// back in its original state from any goroutine, without waiting for the
+// capture goroutine. It is used when the process is about to die.
+type terminalResetter interface {
+	resetTerminal()
+}
+
+// raiseSignal re-delivers sig with its default action. Tests replace it
+// to avoid terminating the test binary.
+var raiseSignal = func(sig os.Signal) {
+	signal.Reset(sig)
+	p, err := os.FindProcess(os.Getpid())
+	if err == nil {
+		err = p.Signal(sig)
+	}
+	if err != nil {
+		os.Exit(2)
+	}
+}
+
+// activeInputs holds the started Inputs, restored by RestoreOnPanic.
+var (
+	activeMu     sync.Mutex
+	activeInputs = map[*inputImpl]struct{}{}
+)
+
+// setActive adds or removes in from the set restored by RestoreOnPanic.
+func setActive(in *inputImpl, active bool) {
+	activeMu.Lock()
+	defer activeMu.Unlock()
+	if active {
+		activeInputs[in] = struct{}{}
+	} else {
+		delete(activeInputs, in)
+	}
+}
+
+// resetAll restores the terminal of every started Input. Start and Stop
+// update the set while holding the Input's lock, which resetTerminal takes,
+// so the set is copied and activeMu released before resetting.
+func resetAll() {
+	activeMu.Lock()
+	inputs := slices.Collect(maps.Keys(activeInputs))
+	activeMu.Unlock()
+
+	for _, in := range inputs {
+		in.resetTerminal()
+	}
+}
+
+// RestoreOnPanic restores the terminal of every started Input if the
+// calling goroutine is panicking, then continues the panic. Deferring it at
+// the top of main (and of any goroutine that may panic while input is
+// running) keeps a crash from leaving the terminal in raw mode:
+//
+//	func main() {
+//	    defer input.RestoreOnPanic()
+//	    ...
+//	}
+//
+// It must be deferred directly, not called from another deferred function.
+func RestoreOnPanic() {
+	r := recover()
+	if r == nil {
+		return
+	}
+	resetAll()
+	panic(r)
+}
+
+// resetTerminal restores the terminal immediately, if the input system is
+// running and the backend supports it.
+func (in *inputImpl) resetTerminal() {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	if !in.started {
+		return
+	}
+	if r, ok := in.backend.(terminalResetter); ok {
+		r.resetTerminal()
+	}
+}
+
+// startGuard installs the signal handlers of WithSignalGuard. The returned
+// function removes them.
+func (in *inputImpl) startGuard() (stop func()) {
+	sigs := make(chan os.Signal, 1)
+	quit := make(chan struct{})
+	signal.Notify(sigs, guardSignals...)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		select {
+		case sig := <-sigs:
+			in.resetTerminal()
+			signal.Stop(sigs)
+			raiseSignal(sig)
+		case <-quit:
+		}
+	}()
+
+	return func() {
+		signal.Stop(sigs)
+		close(quit)
+		wg.Wait()
+	}
+}
@@ -0,0 +1,99 @@
+package input
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// resetCount returns how many times the fake backend was reset.
+func (b *fakeBackend) resetCount() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.resets
+}
+
+// TestRestoreOnPanic validates that RestoreOnPanic resets the terminal of
+// started Inputs and lets the panic continue.
+func TestRestoreOnPanic(t *testing.T) {
+	backend := newFakeBackend()
+	in := newTestInput(backend)
+	if err := in.Start(); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	defer in.Stop()
+
+	stopped := newFakeBackend()
+	idle := newTestInput(stopped)
+
+	func() {
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Errorf("recovered %v, want the original panic", r)
+			}
+		}()
+		defer RestoreOnPanic()
+		panic("boom")
+	}()
+
+	if got := backend.resetCount(); got != 1 {
+		t.Errorf("started input reset %d times, want 1", got)
+	}
+	if got := stopped.resetCount(); got != 0 || idle.started {
+		t.Errorf("unstarted input reset %d times, want 0", got)
+	}
+
+	// Without a panic nothing happens
+	func() {
+		defer RestoreOnPanic()
+	}()
+	if got := backend.resetCount(); got != 1 {
+		t.Errorf("input reset %d times without a panic, want 1", got)
+	}
+}
+
+// TestRestoreOnPanicDuringStartStop validates that resetting every Input
+// does not deadlock with an Input starting and stopping concurrently.
+func TestRestoreOnPanicDuringStartStop(t *testing.T) {
+	in := newTestInput(newFakeBackend())
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for end := time.Now().Add(500 * time.Millisecond); time.Now().Before(end); {
+			if err := in.Start(); err != nil {
+				t.Errorf("Start() error: %v", err)
+				break
+			}
+			in.Stop()
+		}
+		close(stop)
+	}()
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					resetAll()
+				}
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("resetAll deadlocked with Start/Stop")
+	}
+}
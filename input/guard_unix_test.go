@@ -0,0 +1,43 @@
+//go:build !windows
+// +build !windows
+
+package input
+
+import (
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// TestSignalGuard validates that a guarded signal resets the terminal and
+// is re-raised, and that Stop removes the guard.
+func TestSignalGuard(t *testing.T) {
+	raised := make(chan os.Signal, 1)
+	defer func(orig func(os.Signal)) { raiseSignal = orig }(raiseSignal)
+	raiseSignal = func(sig os.Signal) { raised <- sig }
+
+	backend := newFakeBackend()
+	in := New(WithSignalGuard()).(*inputImpl)
+	in.backend = backend
+	if err := in.Start(); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	defer in.Stop()
+
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGHUP); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case sig := <-raised:
+		if sig != syscall.SIGHUP {
+			t.Errorf("re-raised %v, want SIGHUP", sig)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("signal was not re-raised")
+	}
+	if got := backend.resetCount(); got != 1 {
+		t.Errorf("terminal reset %d times, want 1", got)
+	}
+}
@@ -1,46 +1,131 @@
 package input
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"iter"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 // inputImpl is the concrete implementation of the Input interface.
 // It manages a background goroutine for event capture and maintains
 // a buffered channel for event delivery.
+//
+// An inputImpl can be started again after Stop: each Start creates fresh
+// channels, so the channels of a previous run are never reused.
 type inputImpl struct {
-	backend   Backend
-	events    chan Event
-	done      chan struct{}
-	wg        sync.WaitGroup
-	mu        sync.RWMutex
-	keyState  map[Key]bool
-	started   bool
-	stopping  bool
-	stopOnce  sync.Once
+	backend  Backend
+	events   chan Event
+	done     chan struct{}
+	wg       sync.WaitGroup
+	mu       sync.RWMutex
+	keyState map[Key]bool
+	mods     Modifier
+	started  bool
+	stopping bool
+
+	// captureStop tells the running capture goroutine to exit. It is
+	// separate from done so Release can pause capture without ending the
+	// session.
+	captureStop chan struct{}
+
+	// stopped is set once Stop has closed events and done, so the next
+	// Start knows to create new ones.
+	stopped bool
+
+	// ended is closed, events is closed and err is set when the session's
+	// capture ends, by Stop or on its own; captureEnded records that.
+	ended        chan struct{}
+	err          error
+	captureEnded bool
+
+	// lifecycle serializes Start and Stop, so concurrent calls to Stop all
+	// return after the terminal is restored.
+	lifecycle sync.Mutex
+
+	// bufferSize is the capacity of each events channel.
+	bufferSize int
+
+	// signalGuard installs signal handlers while started; stopGuard
+	// removes them.
+	signalGuard bool
+	stopGuard   func()
+
+	// overflow is the policy applied when events is full; lastSent is the
+	// most recently queued event, used by OverflowCoalesce. backlog holds
+	// events waiting for room under OverflowBlock and OverflowCoalesce; it
+	// is used by one capture goroutine at a time and survives Release.
+	overflow  OverflowPolicy
+	lastSent  Event
+	backlog   []Event
+	dropped   atomic.Uint64
+	coalesced atomic.Uint64
+
+	// inferReleases enables key-up synthesis with the given thresholds;
+	// now timestamps synthesized events.
+	inferReleases   bool
+	releaseDelay    time.Duration
+	releaseInterval time.Duration
+	now             func() time.Time
+
+	// frame is the snapshot of the last Update, guarded by frameMu.
+	// frameReset tells the next Update that key-ups may have been missed.
+	frameMu    sync.Mutex
+	frame      Snapshot
+	frameReset atomic.Bool
+
+	// middleware is the pipeline captured events pass through first;
+	// Use replaces it under mu.
+	middleware atomic.Pointer[pipeline]
+
+	// subs are the open subscriptions, closed by Stop.
+	subs subscribers
+
+	// repeats classifies autorepeats. It is used by one capture goroutine
+	// at a time and outlives sessions, so learned timing is kept.
+	repeats *repeatDetector
 }
 
 // New creates a new Input instance with the appropriate backend
-// for the current platform.
-func New() Input {
+// for the current platform. Options customize decoding and delivery;
+// with no options New uses the defaults.
+func New(opts ...Option) Input {
+	cfg := newConfig(opts)
 	return &inputImpl{
-		backend:  newBackend(),
-		events:   make(chan Event, 100),
-		done:     make(chan struct{}),
-		keyState: make(map[Key]bool),
+		backend:     newBackend(cfg),
+		events:      make(chan Event, cfg.bufferSize),
+		done:        make(chan struct{}),
+		captureStop: make(chan struct{}),
+		ended:       make(chan struct{}),
+		keyState:    make(map[Key]bool),
+		bufferSize:  cfg.bufferSize,
+		overflow:    cfg.overflow,
+		signalGuard: cfg.signalGuard,
+
+		inferReleases:   cfg.inferReleases,
+		releaseDelay:    cfg.releaseDelay,
+		releaseInterval: cfg.releaseInterval,
+		now:             cfg.now,
+		repeats:         newRepeatDetector(cfg.repeatDelay, cfg.repeatInterval),
 	}
 }
 
 // Start initializes the input system and begins event capture.
 // It enters raw mode and spawns a background goroutine to read events.
+// After Stop, Start begins a new session with empty key state.
 func (in *inputImpl) Start() error {
+	in.lifecycle.Lock()
+	defer in.lifecycle.Unlock()
+
 	in.mu.Lock()
 	defer in.mu.Unlock()
 
 	if in.started {
-		return fmt.Errorf("input already started")
+		return ErrAlreadyStarted
 	}
 
 	// Initialize backend (enter raw mode)
@@ -48,11 +133,29 @@ func (in *inputImpl) Start() error {
 		return fmt.Errorf("failed to initialize backend: %w", err)
 	}
 
+	// A previous session closed its channels: start over with new ones
+	if in.stopped {
+		in.events = make(chan Event, in.bufferSize)
+		in.done = make(chan struct{})
+		in.ended = make(chan struct{})
+		in.err = nil
+		in.captureEnded = false
+		in.keyState = make(map[Key]bool)
+		in.mods = ModNone
+		in.lastSent = Event{}
+		in.backlog = nil
+		in.frameReset.Store(true)
+		in.stopped = false
+	}
+
 	// Start capture goroutine
-	in.wg.Add(1)
-	go in.captureLoop()
+	in.startCapture()
 
 	in.started = true
+	setActive(in, true)
+	if in.signalGuard {
+		in.stopGuard = in.startGuard()
+	}
 	return nil
 }
 
@@ -61,67 +164,335 @@ func (in *inputImpl) Start() error {
 // and restores the terminal to its original state.
 // Safe to call multiple times (idempotent).
 func (in *inputImpl) Stop() {
-	// Use sync.Once to prevent double-close panics and race conditions
-	in.stopOnce.Do(func() {
-		in.mu.Lock()
-		if !in.started {
-			in.mu.Unlock()
+	in.lifecycle.Lock()
+	defer in.lifecycle.Unlock()
+
+	in.mu.Lock()
+	if !in.started {
+		in.mu.Unlock()
+		return
+	}
+	in.stopping = true
+	events, done, captureStop := in.events, in.done, in.captureStop
+	stopGuard := in.stopGuard
+	in.stopGuard = nil
+	in.mu.Unlock()
+
+	// Remove signal handlers; the guard goroutine takes in.mu
+	if stopGuard != nil {
+		stopGuard()
+	}
+
+	// Signal shutdown - safe because lifecycle ensures a single close
+	close(done)
+	stopCapture(in.backend, captureStop, &in.wg)
+
+	// Now we can safely clean up with the lock
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	// Restore terminal state
+	_ = in.backend.Restore()
+
+	// Mark as stopped
+	setActive(in, false)
+	in.started = false
+	in.stopping = false
+	in.stopped = true
+
+	// Close events channel, unless capture already ended, and drain
+	in.endCapture(ErrInputClosed)
+	for range events {
+	}
+
+	in.subs.closeAll()
+}
+
+// endCapture ends the current session's capture for reason, unless it has
+// already ended: it closes the event queue, records reason for Err and
+// closes the Done channel. The caller must hold in.mu.
+func (in *inputImpl) endCapture(reason error) {
+	if in.captureEnded {
+		return
+	}
+	in.captureEnded = true
+	in.err = reason
+	close(in.events)
+	close(in.ended)
+}
+
+// captureFailed ends the session's capture when the capture goroutine
+// exits on its own, once held-back events are queued. Queued events can
+// still be received; Poll reports shutdown once they are consumed.
+func (in *inputImpl) captureFailed(capture *captureState, stop chan struct{}, reason error) {
+	for len(in.backlog) > 0 {
+		select {
+		case capture.events <- in.backlog[0]:
+			in.sent()
+		case <-stop:
 			return
 		}
-		in.stopping = true
+	}
+
+	in.mu.Lock()
+	select {
+	case <-stop:
+		// Stopped or released: not a failure
 		in.mu.Unlock()
+		return
+	default:
+	}
+	in.endCapture(reason)
+	in.mu.Unlock()
 
-		// Signal shutdown - safe because stopOnce ensures single execution
-		close(in.done)
+	in.subs.closeAll()
+}
 
-		// Wait for capture goroutine to exit
-		in.wg.Wait()
+// Err returns why the current session's capture ended, or nil while it
+// is running.
+func (in *inputImpl) Err() error {
+	in.mu.RLock()
+	defer in.mu.RUnlock()
+	return in.err
+}
+
+// Done returns a channel closed when the current session's capture ends.
+func (in *inputImpl) Done() <-chan struct{} {
+	in.mu.RLock()
+	defer in.mu.RUnlock()
+	return in.ended
+}
 
-		// Now we can safely clean up with the lock
-		in.mu.Lock()
-		defer in.mu.Unlock()
+// Release hands the terminal to fn, typically to run a subprocess such as
+// an editor, then takes it back. Events queued before the call remain
+// queued, and Poll calls blocked meanwhile keep waiting.
+func (in *inputImpl) Release(fn func() error) error {
+	in.lifecycle.Lock()
+	defer in.lifecycle.Unlock()
 
-		// Restore terminal state
-		_ = in.backend.Restore()
+	in.mu.RLock()
+	started, captureStop := in.started, in.captureStop
+	in.mu.RUnlock()
 
-		// Mark as stopped
-		in.started = false
+	if !started {
+		// Nothing to release
+		return fn()
+	}
 
-		// Close events channel and drain
-		close(in.events)
-		for range in.events {
+	// Stop capture and return the terminal to its original state
+	stopCapture(in.backend, captureStop, &in.wg)
+	in.mu.Lock()
+	_ = in.backend.Restore()
+	in.mu.Unlock()
+
+	fnErr := fn()
+
+	in.mu.Lock()
+	err := in.backend.Init()
+	if err == nil {
+		// Keys typed while released belong to fn, not to us
+		if d, ok := in.backend.(typeaheadDiscarder); ok {
+			d.discardTypeahead()
+		}
+		// Key-ups were missed while released
+		clear(in.keyState)
+		in.mods = ModNone
+		in.frameReset.Store(true)
+		if !in.captureEnded {
+			in.startCapture()
 		}
-	})
+		in.mu.Unlock()
+		return fnErr
+	}
+
+	// The terminal cannot be taken back: end the session as Stop would
+	err = fmt.Errorf("failed to reinitialize backend: %w", err)
+	stopGuard := in.stopGuard
+	in.stopGuard = nil
+	setActive(in, false)
+	in.started = false
+	in.stopped = true
+	close(in.done)
+	in.endCapture(err)
+	for range in.events {
+	}
+	in.mu.Unlock()
+	in.subs.closeAll()
+
+	if stopGuard != nil {
+		stopGuard()
+	}
+	return errors.Join(fnErr, err)
+}
+
+// typeaheadDiscarder is implemented by backends that can discard input
+// received while the terminal was released.
+type typeaheadDiscarder interface {
+	discardTypeahead()
+}
+
+// startCapture starts a capture goroutine feeding the current session.
+// The caller must hold in.mu.
+func (in *inputImpl) startCapture() {
+	in.captureStop = make(chan struct{})
+	in.wg.Add(1)
+	go in.captureLoop(in.events, in.captureStop)
+}
+
+// stopCapture stops the capture goroutine started with captureStop and
+// waits for it to exit.
+func stopCapture(backend Backend, captureStop chan struct{}, wg *sync.WaitGroup) {
+	close(captureStop)
+
+	// Wake the capture goroutine if it is blocked reading the terminal
+	backend.Cancel()
+
+	// Wait for capture goroutine to exit
+	wg.Wait()
+}
+
+// queue returns the channels of the current session.
+func (in *inputImpl) queue() (events chan Event, done chan struct{}) {
+	in.mu.RLock()
+	defer in.mu.RUnlock()
+	return in.events, in.done
 }
 
 // Poll returns the next available event, blocking until one is available
 // or the system is shutting down.
 // Returns (event, true) if an event is available, or (zero, false) on shutdown.
 func (in *inputImpl) Poll() (Event, bool) {
+	events, done := in.queue()
 	select {
-	case event, ok := <-in.events:
+	case event, ok := <-events:
 		if !ok {
 			return Event{}, false
 		}
-		in.updateKeyState(event)
 		return event, true
-	case <-in.done:
+	case <-done:
 		return Event{}, false
 	}
 }
 
+// PollContext returns the next available event, blocking until one is
+// available, ctx is done, or the system is shutting down.
+func (in *inputImpl) PollContext(ctx context.Context) (Event, error) {
+	if err := ctx.Err(); err != nil {
+		return Event{}, err
+	}
+
+	events, done := in.queue()
+	select {
+	case event, ok := <-events:
+		if !ok {
+			return Event{}, ErrInputClosed
+		}
+		return event, nil
+	case <-done:
+		return Event{}, ErrInputClosed
+	case <-ctx.Done():
+		return Event{}, ctx.Err()
+	}
+}
+
+// PollTimeout returns the next available event, blocking for at most d.
+// Returns context.DeadlineExceeded if no event arrived in time.
+func (in *inputImpl) PollTimeout(d time.Duration) (Event, error) {
+	events, done := in.queue()
+	if d <= 0 {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return Event{}, ErrInputClosed
+			}
+			return event, nil
+		case <-done:
+			return Event{}, ErrInputClosed
+		default:
+			return Event{}, context.DeadlineExceeded
+		}
+	}
+
+	// A timer rather than time.After, so it is released as soon as an
+	// event arrives
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case event, ok := <-events:
+		if !ok {
+			return Event{}, ErrInputClosed
+		}
+		return event, nil
+	case <-done:
+		return Event{}, ErrInputClosed
+	case <-timer.C:
+		return Event{}, context.DeadlineExceeded
+	}
+}
+
+// Events returns the event queue of the current session.
+func (in *inputImpl) Events() <-chan Event {
+	events, _ := in.queue()
+	return events
+}
+
+// All returns an iterator over events, ending when the system stops.
+func (in *inputImpl) All() iter.Seq[Event] {
+	return func(yield func(Event) bool) {
+		for {
+			event, ok := in.Poll()
+			if !ok || !yield(event) {
+				return
+			}
+		}
+	}
+}
+
 // Next returns the next available event without blocking.
 // Returns nil if no event is available.
 func (in *inputImpl) Next() *Event {
+	var event Event
+	if !in.NextInto(&event) {
+		return nil
+	}
+	return &event
+}
+
+// NextInto stores the next available event in *event without blocking.
+// Returns false, leaving *event unchanged, if no event is available.
+func (in *inputImpl) NextInto(event *Event) bool {
+	events, _ := in.queue()
 	select {
-	case event := <-in.events:
-		in.updateKeyState(event)
-		return &event
+	case e, ok := <-events:
+		if !ok {
+			return false
+		}
+		*event = e
+		return true
 	default:
-		return nil
+		return false
 	}
 }
 
+// DrainInto copies pending events into buf without blocking and returns
+// how many were copied.
+func (in *inputImpl) DrainInto(buf []Event) int {
+	events, _ := in.queue()
+	for n := range buf {
+		select {
+		case e, ok := <-events:
+			if !ok {
+				return n
+			}
+			buf[n] = e
+		default:
+			return n
+		}
+	}
+	return len(buf)
+}
+
 // IsPressed returns true if the specified key is currently pressed.
 func (in *inputImpl) IsPressed(k Key) bool {
 	in.mu.RLock()
@@ -129,31 +500,142 @@ func (in *inputImpl) IsPressed(k Key) bool {
 	return in.keyState[k]
 }
 
+// Modifiers returns the modifiers of the most recently captured key event.
+func (in *inputImpl) Modifiers() Modifier {
+	in.mu.RLock()
+	defer in.mu.RUnlock()
+	return in.mods
+}
+
+// Use appends middleware to the pipeline.
+func (in *inputImpl) Use(mw ...Middleware) {
+	stages := make([]stage, 0, len(mw))
+	for _, m := range mw {
+		if m != nil {
+			stages = append(stages, stage{mw: m})
+		}
+	}
+	in.addStages(stages)
+}
+
+// UseExpander appends expanders to the pipeline.
+func (in *inputImpl) UseExpander(x ...Expander) {
+	stages := make([]stage, 0, len(x))
+	for _, e := range x {
+		if e != nil {
+			stages = append(stages, stage{expand: e})
+		}
+	}
+	in.addStages(stages)
+}
+
+// addStages replaces the pipeline with one ending in stages.
+func (in *inputImpl) addStages(stages []stage) {
+	if len(stages) == 0 {
+		return
+	}
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	var p pipeline
+	if current := in.middleware.Load(); current != nil {
+		p = *current
+	}
+	p = p.with(stages...)
+	in.middleware.Store(&p)
+}
+
+// Subscribe registers a new subscription to the event stream.
+func (in *inputImpl) Subscribe(opts ...SubscribeOption) *Subscription {
+	return in.subs.add(opts)
+}
+
+// Update drains the queued events into a new Snapshot, makes it the current
+// snapshot and returns it.
+func (in *inputImpl) Update() Snapshot {
+	in.frameMu.Lock()
+	defer in.frameMu.Unlock()
+
+	frame := nextSnapshot(in.frame, in.now(), in.frameReset.Swap(false))
+	events, _ := in.queue()
+drain:
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				break drain
+			}
+			frame.apply(event)
+		default:
+			break drain
+		}
+	}
+	in.frame = frame
+	return frame
+}
+
+// Snapshot returns the snapshot taken by the last Update.
+func (in *inputImpl) Snapshot() Snapshot {
+	in.frameMu.Lock()
+	defer in.frameMu.Unlock()
+	return in.frame
+}
+
 // captureLoop is the background goroutine that reads events from the backend
-// and feeds them into the event channel.
-func (in *inputImpl) captureLoop() {
+// and feeds them into the event channel of its session until stop is closed.
+// It never waits for the consumer, so key state and subscriptions stay
+// current while the queue is full.
+func (in *inputImpl) captureLoop(events chan Event, stop chan struct{}) {
 	defer in.wg.Done()
 
+	// Middleware and subscription filters run here, where callers cannot
+	// recover: restore the terminal before a panic ends the process
+	defer func() {
+		if r := recover(); r != nil {
+			in.resetTerminal()
+			capturePanic(r)
+		}
+	}()
+
 	const (
 		maxConsecutiveErrors = 10
 		errorBackoff         = 100 * time.Millisecond
 	)
 
+	// Keys may have been let go since the last session
+	in.repeats.endRun()
+
+	// The backend is read from a second goroutine, so held-back events can
+	// be queued, and key-ups synthesized, while no input arrives
+	capture := captureState{
+		repeats: in.repeats,
+		reads:   make(chan readResult),
+		events:  events,
+	}
+	if in.inferReleases {
+		capture.releases = newReleaseInferrer(in.releaseDelay, in.releaseInterval)
+	}
+	in.wg.Add(1)
+	go in.readLoop(capture.reads, stop)
+
+	// emit hands an event that came out of the middleware to key state,
+	// subscribers and the queue
+	emit := func(event Event) {
+		in.dispatch(events, event)
+	}
+
 	consecutiveErrors := 0
 
 	for {
-		// Check if we should exit
-		select {
-		case <-in.done:
+		// Read event from backend (blocking)
+		event, err := in.readEvent(&capture, stop)
+		if errors.Is(err, errCaptureStopped) {
 			return
-		default:
 		}
-
-		// Read event from backend (blocking)
-		event, err := in.backend.ReadEvent()
 		if err != nil {
 			if err == io.EOF {
 				// Backend closed, exit gracefully
+				in.captureFailed(&capture, stop, fmt.Errorf("%w: %w", ErrInputClosed, err))
 				return
 			}
 
@@ -162,37 +644,272 @@ func (in *inputImpl) captureLoop() {
 			if consecutiveErrors >= maxConsecutiveErrors {
 				// Too many errors, something is seriously wrong
 				// Exit gracefully to prevent infinite error loop
+				in.captureFailed(&capture, stop, fmt.Errorf("%w: %w", ErrTooManyErrors, err))
 				return
 			}
 
 			// Back off to avoid CPU spin on persistent errors
-			select {
-			case <-time.After(errorBackoff):
-				continue
-			case <-in.done:
+			if !in.wait(&capture, stop, time.After(errorBackoff)) {
 				return
 			}
+			continue
 		}
 
 		// Reset error counter on successful read
 		consecutiveErrors = 0
 
-		// Try to send event to channel
+		// Middleware may rewrite, drop or expand the event
+		if mw := in.middleware.Load(); mw != nil {
+			mw.run(event, emit)
+		} else {
+			emit(event)
+		}
+	}
+}
+
+// capturePanic re-raises a panic recovered on the capture goroutine. Tests
+// replace it to observe the panic without crashing the test binary.
+var capturePanic = func(r any) { panic(r) }
+
+// errCaptureStopped is returned by readEvent when stop is closed.
+var errCaptureStopped = errors.New("capture stopped")
+
+// readResult is the outcome of one Backend.ReadEvent call.
+type readResult struct {
+	event Event
+	err   error
+}
+
+// readLoop reads events from the backend into reads until stop is closed
+// or the backend is closed.
+func (in *inputImpl) readLoop(reads chan<- readResult, stop chan struct{}) {
+	defer in.wg.Done()
+
+	for {
+		event, err := in.backend.ReadEvent()
+		select {
+		case reads <- readResult{event, err}:
+		case <-stop:
+			return
+		}
+		if err == io.EOF {
+			return
+		}
+	}
+}
+
+// captureState is the per-session state of a capture goroutine.
+type captureState struct {
+	repeats *repeatDetector
+
+	// reads is fed by readLoop; events is the session's queue.
+	reads  chan readResult
+	events chan Event
+
+	// releases is nil unless release inference is enabled. pressed is a
+	// press waiting for its key's synthesized key-up to be delivered
+	// first.
+	releases   *releaseInferrer
+	pressed    Event
+	hasPressed bool
+}
+
+// readEvent returns the next event for the capture goroutine, classified
+// by the repeat detector, or a synthesized key-up if a held key's deadline
+// passes first. While waiting it moves held-back events into the queue.
+// It returns errCaptureStopped if stop is closed.
+func (in *inputImpl) readEvent(capture *captureState, stop chan struct{}) (Event, error) {
+	if capture.hasPressed {
+		capture.hasPressed = false
+		return capture.pressed, nil
+	}
+	inferrer := capture.releases
+
+	var timer *time.Timer
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+
+	for {
+		var deadline <-chan time.Time
+		if inferrer != nil {
+			if event, ok := inferrer.expired(time.Now()); ok {
+				event.Timestamp = in.now()
+				return capture.repeats.observe(event, time.Now()), nil
+			}
+			if next, ok := inferrer.next(); ok {
+				if timer == nil {
+					timer = time.NewTimer(time.Until(next))
+				} else {
+					timer.Reset(time.Until(next))
+				}
+				deadline = timer.C
+			}
+		}
+
+		// Offer the oldest held-back event while waiting
+		var send chan Event
+		var pending Event
+		if len(in.backlog) > 0 {
+			send, pending = capture.events, in.backlog[0]
+		}
+
+		select {
+		case r := <-capture.reads:
+			if r.err != nil {
+				return Event{}, r.err
+			}
+			now := time.Now()
+			event := capture.repeats.observe(r.event, now)
+			if inferrer != nil {
+				if up, ok := inferrer.observe(event, now); ok {
+					// The key was let go unseen: release it, then press
+					// it again. The repeat detector has already seen the
+					// press, so the key-up is not shown to it.
+					capture.pressed, capture.hasPressed = event, true
+					up.Timestamp = in.now()
+					return up, nil
+				}
+			}
+			return event, nil
+		case send <- pending:
+			in.sent()
+		case <-deadline:
+		case <-stop:
+			return Event{}, errCaptureStopped
+		}
+	}
+}
+
+// wait moves held-back events into the queue until timeout fires, without
+// reading. It returns false if stop is closed.
+func (in *inputImpl) wait(capture *captureState, stop chan struct{}, timeout <-chan time.Time) bool {
+	for {
+		var send chan Event
+		var pending Event
+		if len(in.backlog) > 0 {
+			send, pending = capture.events, in.backlog[0]
+		}
+
+		select {
+		case send <- pending:
+			in.sent()
+		case <-timeout:
+			return true
+		case <-stop:
+			return false
+		}
+	}
+}
+
+// sent removes the oldest held-back event after it was queued.
+func (in *inputImpl) sent() {
+	in.lastSent = in.backlog[0]
+	// Release the slot's Raw bytes
+	in.backlog[0] = Event{}
+	in.backlog = in.backlog[1:]
+}
+
+// dispatch records event and delivers it to subscribers and the queue.
+func (in *inputImpl) dispatch(events chan Event, event Event) {
+	// Track state before queuing, so it does not depend on consumers
+	in.updateKeyState(event)
+
+	// Subscribers see every event, whatever the main queue does
+	in.subs.broadcast(event)
+
+	in.deliver(events, event)
+}
+
+// maxBacklog bounds the events held back while the queue is full under
+// OverflowBlock and OverflowCoalesce. Beyond it new events are dropped, as
+// the terminal's own buffer would once it filled.
+const maxBacklog = 4096
+
+// deliver queues event on events according to the overflow policy,
+// without blocking.
+func (in *inputImpl) deliver(events chan Event, event Event) {
+	// Fast path: there is room in the queue and nothing is waiting for it
+	if len(in.backlog) == 0 {
 		select {
-		case in.events <- event:
-			// Event sent successfully
-		case <-in.done:
-			// Shutdown signal received
+		case events <- event:
+			in.lastSent = event
 			return
+		default:
 		}
 	}
+
+	switch in.overflow {
+	case OverflowDropNewest:
+		in.dropped.Add(1)
+		return
+
+	case OverflowDropOldest:
+		// An unbuffered queue holds nothing to discard; the new event is
+		// dropped instead
+		for cap(events) > 0 {
+			// Discard the oldest event, unless a consumer got to it first
+			select {
+			case <-events:
+				in.dropped.Add(1)
+			default:
+			}
+			select {
+			case events <- event:
+				in.lastSent = event
+				return
+			default:
+			}
+		}
+		in.dropped.Add(1)
+		return
+
+	case OverflowCoalesce:
+		// The queue is full, so the last event queued or held back is
+		// still waiting
+		last := in.lastSent
+		if n := len(in.backlog); n > 0 {
+			last = in.backlog[n-1]
+		}
+		if sameKeyEvent(event, last) {
+			in.coalesced.Add(1)
+			return
+		}
+	}
+
+	// Hold the event back until the consumer makes room
+	if len(in.backlog) >= maxBacklog {
+		in.dropped.Add(1)
+		return
+	}
+	in.backlog = append(in.backlog, event)
+}
+
+// OverflowStats returns the number of events lost to the overflow policy.
+func (in *inputImpl) OverflowStats() OverflowStats {
+	return OverflowStats{
+		Dropped:   in.dropped.Load(),
+		Coalesced: in.coalesced.Load(),
+	}
 }
 
-// updateKeyState updates the internal key state tracking.
+// updateKeyState updates the internal key and modifier state tracking.
+// It is called by the capture goroutine for every event read.
 func (in *inputImpl) updateKeyState(event Event) {
 	in.mu.Lock()
 	defer in.mu.Unlock()
 
+	if event.Type == EventResume {
+		// Key-ups were missed while suspended
+		clear(in.keyState)
+		in.mods = ModNone
+		return
+	}
+
+	in.mods = event.Modifiers
+
 	if event.Pressed {
 		in.keyState[event.Key] = true
 	} else {
@@ -0,0 +1,132 @@
+package input
+
+import "time"
+
+// Default release-inference thresholds. Most systems start autorepeat
+// 250-660ms after a press and repeat every 30-50ms; the defaults leave
+// headroom above both.
+const (
+	defaultReleaseDelay    = 700 * time.Millisecond
+	defaultReleaseInterval = 100 * time.Millisecond
+)
+
+// heldID identifies a held key. Characters without a Key of their own
+// are all KeyUnknown and are told apart by their rune.
+type heldID struct {
+	key  Key
+	char rune
+}
+
+// heldKey is a key the inferrer considers held down.
+type heldKey struct {
+	// press is the most recent key-down for the key and at is when it
+	// was captured.
+	press Event
+	at    time.Time
+
+	// repeating is set while the presses are autorepeats.
+	repeating bool
+}
+
+// releaseInferrer synthesizes key-up events for terminals that only report
+// key presses. A key is considered held from its press, and autorepeats
+// (as classified by the repeat detector) keep it held. The key is released
+// once presses stop arriving: after delay if it has not repeated, after
+// interval once it has. A press of a held key that is not a repeat means
+// the key was let go and pressed again, so it is released first.
+//
+// Terminals repeat only the most recently pressed key, so holding one key
+// while pressing another ends the first key's repeats and it is released
+// after interval even though it is still down.
+//
+// A releaseInferrer is owned by a single capture goroutine.
+type releaseInferrer struct {
+	delay    time.Duration
+	interval time.Duration
+	held     map[heldID]heldKey
+
+	// disabled is set once the terminal reports a real key-up, making
+	// inference unnecessary.
+	disabled bool
+}
+
+// newReleaseInferrer returns an inferrer with the given thresholds.
+func newReleaseInferrer(delay, interval time.Duration) *releaseInferrer {
+	return &releaseInferrer{
+		delay:    delay,
+		interval: interval,
+		held:     make(map[heldID]heldKey),
+	}
+}
+
+// observe records an event captured at now, after the repeat detector has
+// classified it. If the event presses a held key again, observe returns
+// that key's synthesized key-up, which must be delivered before the event.
+func (r *releaseInferrer) observe(event Event, now time.Time) (Event, bool) {
+	if r.disabled {
+		return Event{}, false
+	}
+
+	switch {
+	case event.Type == EventResume:
+		// Key-ups were missed while suspended
+		clear(r.held)
+	case !event.Pressed:
+		// The terminal reports key-ups itself
+		r.disabled = true
+		clear(r.held)
+	default:
+		id := heldID{key: event.Key, char: event.Rune}
+		k, held := r.held[id]
+		r.held[id] = heldKey{press: event, at: now, repeating: event.Repeat}
+		if held && !event.Repeat {
+			return release(k.press, now), true
+		}
+	}
+	return Event{}, false
+}
+
+// deadline returns when key k is released if no press arrives first.
+func (r *releaseInferrer) deadline(k heldKey) time.Time {
+	if k.repeating {
+		return k.at.Add(r.interval)
+	}
+	return k.at.Add(r.delay)
+}
+
+// expired removes one key whose deadline is at or before now and returns
+// its synthesized key-up.
+func (r *releaseInferrer) expired(now time.Time) (Event, bool) {
+	for id, k := range r.held {
+		if !r.deadline(k).After(now) {
+			delete(r.held, id)
+			return release(k.press, now), true
+		}
+	}
+	return Event{}, false
+}
+
+// release returns the synthesized key-up, at now, of the key pressed by
+// press.
+func release(press Event, now time.Time) Event {
+	event := press
+	event.Timestamp = now
+	event.Pressed = false
+	event.Repeat = false
+	event.Synthetic = true
+	event.Raw = ""
+	return event
+}
+
+// next returns the earliest deadline of a held key, or false if no key
+// is held.
+func (r *releaseInferrer) next() (time.Time, bool) {
+	var earliest time.Time
+	found := false
+	for _, k := range r.held {
+		if d := r.deadline(k); !found || d.Before(earliest) {
+			earliest, found = d, true
+		}
+	}
+	return earliest, found
+}
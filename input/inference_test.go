@@ -0,0 +1,183 @@
+package input
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+// TestReleaseInferrer validates key-up synthesis from press timing, for
+// presses already classified by the repeat detector.
+func TestReleaseInferrer(t *testing.T) {
+	const (
+		delay    = 500 * time.Millisecond
+		interval = 50 * time.Millisecond
+	)
+	t0 := time.Unix(0, 0)
+	ms := func(n int) time.Time { return t0.Add(time.Duration(n) * time.Millisecond) }
+
+	type press struct {
+		at    time.Time
+		event Event
+	}
+	w := Event{Key: KeyW, Pressed: true}
+	wRepeat := Event{Key: KeyW, Pressed: true, Repeat: true}
+	tests := []struct {
+		name        string
+		presses     []press
+		now         time.Time
+		wantRelease []heldID
+	}{
+		{
+			name:    "single press held until delay",
+			presses: []press{{ms(0), w}},
+			now:     ms(499),
+		},
+		{
+			name:        "single press released after delay",
+			presses:     []press{{ms(0), w}},
+			now:         ms(500),
+			wantRelease: []heldID{{key: KeyW}},
+		},
+		{
+			name:    "repeating key held between repeats",
+			presses: []press{{ms(0), w}, {ms(400), wRepeat}, {ms(430), wRepeat}},
+			now:     ms(479),
+		},
+		{
+			name:        "repeating key released after interval",
+			presses:     []press{{ms(0), w}, {ms(400), wRepeat}},
+			now:         ms(450),
+			wantRelease: []heldID{{key: KeyW}},
+		},
+		{
+			name:        "second press released first",
+			presses:     []press{{ms(0), w}, {ms(150), w}},
+			now:         ms(600),
+			wantRelease: []heldID{{key: KeyW}},
+		},
+		{
+			name:    "other key held separately",
+			presses: []press{{ms(0), w}, {ms(10), Event{Key: KeyA, Pressed: true}}},
+			now:     ms(100),
+		},
+		{
+			name: "characters without a key held separately",
+			presses: []press{
+				{ms(0), Event{Key: KeyUnknown, Rune: '!', Pressed: true}},
+				{ms(10), Event{Key: KeyUnknown, Rune: '?', Pressed: true}},
+			},
+			now:         ms(510),
+			wantRelease: []heldID{{KeyUnknown, '!'}, {KeyUnknown, '?'}},
+		},
+		{
+			name:    "resume forgets held keys",
+			presses: []press{{ms(0), w}, {ms(10), Event{Type: EventResume}}},
+			now:     ms(1000),
+		},
+		{
+			name: "real key-up disables inference",
+			presses: []press{
+				{ms(0), w},
+				{ms(10), Event{Key: KeyW, Pressed: false}},
+				{ms(20), Event{Key: KeyA, Pressed: true}},
+				{ms(30), Event{Key: KeyA, Pressed: true}},
+			},
+			now: ms(1000),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newReleaseInferrer(delay, interval)
+			var released []heldID
+			check := func(event Event, at time.Time) {
+				t.Helper()
+				if event.Pressed || !event.Synthetic || event.Raw != "" || event.Timestamp != at {
+					t.Errorf("synthesized event = %+v, want a synthetic key-up at %v", event, at)
+				}
+				released = append(released, heldID{key: event.Key, char: event.Rune})
+			}
+
+			for _, p := range tt.presses {
+				if event, ok := r.observe(p.event, p.at); ok {
+					check(event, p.at)
+				}
+			}
+			for {
+				event, ok := r.expired(tt.now)
+				if !ok {
+					break
+				}
+				check(event, tt.now)
+			}
+
+			if len(released) != len(tt.wantRelease) {
+				t.Fatalf("released %v, want %v", released, tt.wantRelease)
+			}
+			for _, want := range tt.wantRelease {
+				if !slices.Contains(released, want) {
+					t.Errorf("released %v, want %v", released, tt.wantRelease)
+				}
+			}
+		})
+	}
+}
+
+// TestWithReleaseInference validates that a key pressed once is reported
+// released, both as a queued key-up and through IsPressed.
+func TestWithReleaseInference(t *testing.T) {
+	backend := newFakeBackend()
+	in := New(WithReleaseInference(30*time.Millisecond, 10*time.Millisecond)).(*inputImpl)
+	in.backend = backend
+	if err := in.Start(); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	defer in.Stop()
+
+	backend.events <- Event{Key: KeySpace, Pressed: true}
+	event, err := in.PollTimeout(time.Second)
+	if err != nil || event.Key != KeySpace || !event.Pressed {
+		t.Fatalf("PollTimeout() = (%+v, %v), want Space key-down", event, err)
+	}
+
+	event, err = in.PollTimeout(time.Second)
+	if err != nil {
+		t.Fatalf("PollTimeout() error: %v", err)
+	}
+	if event.Key != KeySpace || event.Pressed || !event.Synthetic {
+		t.Errorf("PollTimeout() = %+v, want synthetic Space key-up", event)
+	}
+	if in.IsPressed(KeySpace) {
+		t.Error("IsPressed(KeySpace) = true after inferred release")
+	}
+}
+
+// TestReleaseInferenceDoubleTap validates that pressing a key again before
+// its inferred release delivers a key-up between the two presses, with
+// neither press marked as a repeat.
+func TestReleaseInferenceDoubleTap(t *testing.T) {
+	backend := newFakeBackend()
+	in := New(WithReleaseInference(time.Second, 10*time.Millisecond)).(*inputImpl)
+	in.backend = backend
+	if err := in.Start(); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	defer in.Stop()
+
+	backend.events <- Event{Key: KeyL, Rune: 'l', Pressed: true}
+	time.Sleep(2 * fastRepeatGap)
+	backend.events <- Event{Key: KeyL, Rune: 'l', Pressed: true}
+
+	want := []struct{ pressed, synthetic bool }{{true, false}, {false, true}, {true, false}}
+	for i, w := range want {
+		event, err := in.PollTimeout(time.Second)
+		if err != nil {
+			t.Fatalf("event %d: PollTimeout() error: %v", i, err)
+		}
+		if event.Key != KeyL || event.Pressed != w.pressed || event.Synthetic != w.synthetic || event.Repeat {
+			t.Errorf("event %d = %+v, want Key L, Pressed %v, Synthetic %v, not Repeat",
+				i, event, w.pressed, w.synthetic)
+		}
+	}
+}
@@ -1,5 +1,11 @@
 package input
 
+import (
+	"context"
+	"iter"
+	"time"
+)
+
 // Input defines the keyboard input API for cross-terminal event capture.
 // Implementations provide normalized keyboard events across different terminals
 // and operating systems.
@@ -11,18 +17,23 @@ type Input interface {
 	// prepares the event queue.
 	//
 	// Returns an error if:
-	//   - Terminal initialization fails (permissions, unsupported terminal)
-	//   - The input system is already started
+	//   - Terminal initialization fails (permissions, unsupported terminal);
+	//     the error matches ErrNotTerminal when there is no terminal
+	//   - The input system is already started (ErrAlreadyStarted)
 	//   - Platform-specific backend initialization fails
 	//
-	// Start is safe to call from any goroutine. Use sync.Once internally to
-	// ensure it only initializes once.
+	// Start may be called again after Stop to resume capture, for example
+	// after handing the terminal to a subprocess. Each session starts with
+	// an empty event queue and key state; configuration is preserved.
+	//
+	// Start is safe to call from any goroutine.
 	Start() error
 
 	// Stop restores the terminal to its original state and stops event capture.
 	// It is safe to call multiple times (idempotent).
 	//
 	// All blocked Poll calls will return (zero event, false) after Stop.
+	// Events still queued when Stop is called are discarded.
 	//
 	// Best practice: defer input.Stop() after successful Start().
 	//
@@ -34,12 +45,47 @@ type Input interface {
 	//
 	// Returns:
 	//   - (Event, true): Normal event
-	//   - (zero, false): System shutting down (Stop was called)
+	//   - (zero, false): System shutting down (Stop was called) or capture
+	//     ended (see Err)
 	//
 	// Poll is thread-safe. Multiple goroutines can call Poll, but each event
 	// is delivered to only one caller (channel semantics).
 	Poll() (Event, bool)
 
+	// PollContext blocks until the next keyboard event is available, ctx is
+	// done, or the input system is shutting down.
+	//
+	// Returns:
+	//   - (Event, nil): Normal event
+	//   - (zero, ctx.Err()): ctx was cancelled or its deadline passed
+	//   - (zero, ErrInputClosed): System shutting down (Stop was called)
+	//
+	// PollContext does not start any goroutines and is thread-safe with the
+	// same delivery semantics as Poll.
+	PollContext(ctx context.Context) (Event, error)
+
+	// PollTimeout blocks for at most d waiting for the next keyboard event.
+	// It returns context.DeadlineExceeded if no event arrived in time and
+	// ErrInputClosed if the system is shutting down. A non-positive d
+	// checks for a pending event without blocking.
+	PollTimeout(d time.Duration) (Event, error)
+
+	// Events returns the event queue as a receive-only channel, for use in
+	// select statements alongside timers and other channels. It is the
+	// queue Poll and Next read from, with the same ordering, and each event
+	// is received by only one consumer. The channel is closed by Stop; a
+	// restarted system uses a new channel, so call Events again after Start.
+	Events() <-chan Event
+
+	// All returns an iterator over events for use with range:
+	//
+	//	for ev := range in.All() {
+	//	    ...
+	//	}
+	//
+	// Each iteration calls Poll; the loop ends when the system stops.
+	All() iter.Seq[Event]
+
 	// Next returns the next keyboard event immediately without blocking.
 	//
 	// Returns:
@@ -50,8 +96,27 @@ type Input interface {
 	// Typical usage is in game loops or non-blocking event processing.
 	Next() *Event
 
+	// NextInto is Next without allocation: it stores the next event in
+	// *event and returns true, or returns false if no event is available.
+	// It never blocks.
+	NextInto(event *Event) bool
+
+	// DrainInto copies up to len(buf) pending events into buf, oldest
+	// first, and returns how many it copied. It never blocks and does not
+	// allocate, so a game loop can pull a frame's input in one call with a
+	// reused buffer:
+	//
+	//	n := in.DrainInto(buf[:])
+	//	for _, ev := range buf[:n] {
+	//	    ...
+	//	}
+	//
+	// Events beyond len(buf) stay queued for the next call.
+	DrainInto(buf []Event) int
+
 	// IsPressed returns true if the specified key is currently held down.
-	// State is updated in real-time as events are processed.
+	// State is updated as events are captured, before they are queued, so
+	// it is accurate whether or not anyone consumes the events.
 	//
 	// On platforms supporting key-up events, this reflects actual physical
 	// key state. On platforms without key-up support, state is approximated
@@ -59,6 +124,89 @@ type Input interface {
 	//
 	// IsPressed is thread-safe and safe for concurrent calls.
 	IsPressed(k Key) bool
+
+	// Modifiers returns the modifier keys reported by the most recently
+	// captured key event. Like IsPressed, it does not depend on events
+	// being consumed.
+	//
+	// Modifiers is thread-safe and safe for concurrent calls.
+	Modifiers() Modifier
+
+	// Use appends middleware to the pipeline every captured event passes
+	// through before it reaches key state, subscriptions, Poll/Next and
+	// GameInput. Stages run in the order registered; each may rewrite the
+	// event or drop it. Typical uses are global key remaps, suppressing
+	// repeats of some keys and audit logging.
+	//
+	// Use may be called at any time; events already captured are not
+	// affected. Nil middleware is ignored.
+	Use(mw ...Middleware)
+
+	// UseExpander appends expanders, stages that may turn one event into
+	// several, to the same pipeline as Use.
+	UseExpander(x ...Expander)
+
+	// Subscribe returns a Subscription that receives every captured event,
+	// independently of Poll/Next and of other subscriptions, on its own
+	// buffered channel. Use it when several components need to observe the
+	// full event stream; Poll and Next remain the default consumer and are
+	// unaffected. Key state is updated before events are delivered.
+	//
+	// Subscriptions may be created before Start. Stop closes all of them;
+	// after a restart, subscribe again.
+	//
+	// Subscribe is thread-safe and safe for concurrent calls.
+	Subscribe(opts ...SubscribeOption) *Subscription
+
+	// Done returns a channel that is closed when capture ends: on Stop, or
+	// when capture stops on its own because the terminal was closed or
+	// reads keep failing. Events queued before then can still be received;
+	// Poll reports shutdown once they are consumed. Each Start begins a new
+	// session with a new channel.
+	Done() <-chan struct{}
+
+	// Err returns why capture ended, or nil while it is running. After
+	// Stop it returns ErrInputClosed. If capture ended on its own it returns
+	// an error matching ErrInputClosed and io.EOF when the terminal was
+	// closed (e.g. an SSH hangup), or ErrTooManyErrors and the last read
+	// error when reads kept failing. Stop does not replace that reason.
+	Err() error
+
+	// Update takes a snapshot of the input state at a frame boundary. It
+	// consumes every queued event, so a program should use either Update
+	// or Poll/Next, not both. The returned Snapshot answers edge queries
+	// (JustPressed, JustReleased) for the events since the previous Update,
+	// and level queries (IsPressed, HeldDuration) for the end of the frame.
+	//
+	// Terminals on Unix report presses but no key-ups, so without
+	// WithReleaseInference a key stays IsPressed from its first press on.
+	// JustPressed still reports each press that is not an autorepeat.
+	//
+	// Update is typically called once per frame by a game loop.
+	Update() Snapshot
+
+	// Snapshot returns the snapshot taken by the most recent Update, or
+	// the zero Snapshot before the first. It consumes nothing.
+	Snapshot() Snapshot
+
+	// Release temporarily gives the terminal back, for example to run
+	// $EDITOR or a shell with exec.Cmd.Run. It stops capture, restores the
+	// original terminal mode, disables enabled protocols and calls fn.
+	// When fn returns it re-enters raw mode, discards input typed while
+	// released and resumes capture. Queued events, blocked Poll calls and
+	// GameInput bindings are unaffected; key state is cleared.
+	//
+	// Release returns fn's error. If the terminal cannot be taken back the
+	// input system is stopped and the error is returned as well. If the
+	// system is not started, Release just calls fn. Stop waits for a
+	// Release in progress to finish.
+	Release(fn func() error) error
+
+	// OverflowStats returns how many events have been dropped or coalesced
+	// because the event queue was full (see WithOverflowPolicy).
+	//
+	// OverflowStats is thread-safe and safe for concurrent calls.
+	OverflowStats() OverflowStats
 }
 
 // Backend defines the internal contract for platform-specific terminal I/O.
@@ -97,4 +245,12 @@ type Backend interface {
 	//
 	// Thread-safety: Only called from a single capture goroutine.
 	ReadEvent() (Event, error)
+
+	// Cancel makes a ReadEvent call blocked in another goroutine return
+	// promptly with io.EOF, as do later calls until the next Init. Stop
+	// uses it so shutdown does not wait for another key press.
+	//
+	// Thread-safety: Safe to call from any goroutine between Init and
+	// Restore.
+	Cancel()
 }
@@ -0,0 +1,58 @@
+//go:build linux || darwin
+// +build linux darwin
+
+package input
+
+import (
+	"os"
+	"os/signal"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// resumeWait bounds how long suspend waits for SIGCONT. The stop takes
+// effect before kill returns, so the wait only runs out when the stop was
+// discarded, as it is for orphaned process groups.
+const resumeWait = time.Second
+
+// raiseStop stops the process group, as the terminal does when Ctrl+Z is
+// typed in cooked mode. Tests replace it to avoid stopping the test binary.
+var raiseStop = func() error {
+	return unix.Kill(0, unix.SIGTSTP)
+}
+
+// suspend restores the terminal, stops the process group and, once the
+// process is continued, re-enters raw mode. It returns an EventResume
+// event so the application can redraw.
+func (b *unixBackend) suspend() (Event, error) {
+	cont := make(chan os.Signal, 1)
+	signal.Notify(cont, unix.SIGCONT)
+	defer signal.Stop(cont)
+
+	// Hand the terminal back in the state the shell expects
+	if b.initialized {
+		b.writeProtocols(false)
+		_ = b.setTermios(b.originalState)
+	}
+
+	if err := raiseStop(); err == nil {
+		select {
+		case <-cont:
+		case <-time.After(resumeWait):
+		}
+	}
+
+	// The shell may have changed terminal modes while we were stopped
+	if b.initialized {
+		if err := b.setTermios(b.rawState); err != nil {
+			return Event{}, err
+		}
+		b.writeProtocols(true)
+	}
+
+	event := b.parser.newEvent()
+	event.Type = EventResume
+	event.Pressed = false
+	return event, nil
+}
@@ -12,7 +12,7 @@ import (
 // Before optimization: ~5ms (due to time.Sleep)
 // After optimization: <1ms (using VTIME timeout)
 func BenchmarkEscapeKeyLatency(b *testing.B) {
-	backend := newBackend().(*unixBackend)
+	backend := newBackend(newConfig(nil)).(*unixBackend)
 	if err := backend.Init(); err != nil {
 		b.Skip("Not a terminal environment")
 	}
@@ -38,7 +38,7 @@ func BenchmarkEscapeKeyLatency(b *testing.B) {
 // BenchmarkReadEventLatency measures end-to-end event processing time.
 // This provides a baseline for overall system performance improvements.
 func BenchmarkReadEventLatency(b *testing.B) {
-	backend := newBackend().(*unixBackend)
+	backend := newBackend(newConfig(nil)).(*unixBackend)
 	if err := backend.Init(); err != nil {
 		b.Skip("Not a terminal environment")
 	}
@@ -0,0 +1,51 @@
+package input
+
+// Middleware inspects a captured event before it reaches key state,
+// subscriptions and the event queue. It returns the event to pass on,
+// possibly rewritten, or false to drop it. Register middleware with
+// Input.Use.
+//
+// Middleware runs on the capture goroutine, so it must be fast and must
+// not block or call Stop or Release. A panic in middleware is re-raised
+// after the terminal is restored, and ends the program.
+type Middleware func(Event) (Event, bool)
+
+// Expander is a middleware stage that passes on any number of events for
+// each event it receives, by calling emit once per event; calling emit
+// zero times drops the event. Register expanders with Input.UseExpander.
+// The same restrictions apply as for Middleware.
+type Expander func(event Event, emit func(Event))
+
+// stage is one step of a pipeline: exactly one of mw and expand is set.
+type stage struct {
+	mw     Middleware
+	expand Expander
+}
+
+// pipeline is an ordered list of middleware stages. A pipeline is never
+// modified once in use; registering middleware builds a new one.
+type pipeline []stage
+
+// with returns a new pipeline with stages appended.
+func (p pipeline) with(stages ...stage) pipeline {
+	next := make(pipeline, 0, len(p)+len(stages))
+	return append(append(next, p...), stages...)
+}
+
+// run passes event through the pipeline and calls sink for each event
+// that comes out of it.
+func (p pipeline) run(event Event, sink func(Event)) {
+	for i, s := range p {
+		if s.expand != nil {
+			rest := p[i+1:]
+			s.expand(event, func(e Event) { rest.run(e, sink) })
+			return
+		}
+
+		var ok bool
+		if event, ok = s.mw(event); !ok {
+			return
+		}
+	}
+	sink(event)
+}
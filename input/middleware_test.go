@@ -0,0 +1,130 @@
+package input
+
+import (
+	"testing"
+	"time"
+)
+
+// TestPipeline validates that stages run in order and can rewrite, drop
+// and expand events.
+func TestPipeline(t *testing.T) {
+	remap := stage{mw: func(e Event) (Event, bool) {
+		if e.Key == KeyW {
+			e.Key = KeyUp
+		}
+		return e, true
+	}}
+	dropRepeats := stage{mw: func(e Event) (Event, bool) { return e, !e.Repeat }}
+	double := stage{expand: func(e Event, emit func(Event)) {
+		emit(e)
+		emit(e)
+	}}
+	dropAll := stage{expand: func(Event, func(Event)) {}}
+
+	tests := []struct {
+		name  string
+		p     pipeline
+		event Event
+		want  []Key
+	}{
+		{"empty", nil, Event{Key: KeyA}, []Key{KeyA}},
+		{"rewrite", pipeline{remap}, Event{Key: KeyW}, []Key{KeyUp}},
+		{"drop", pipeline{dropRepeats}, Event{Key: KeyA, Repeat: true}, nil},
+		{"expand then rewrite", pipeline{double, remap}, Event{Key: KeyW}, []Key{KeyUp, KeyUp}},
+		{"drop before expand", pipeline{dropRepeats, double}, Event{Key: KeyA, Repeat: true}, nil},
+		{"expander drops", pipeline{dropAll, remap}, Event{Key: KeyW}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []Key
+			tt.p.run(tt.event, func(e Event) { got = append(got, e.Key) })
+			if len(got) != len(tt.want) {
+				t.Fatalf("pipeline emitted %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("pipeline emitted %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+// TestUse validates that middleware registered on Input applies before key
+// state and the event queue, in registration order.
+func TestUse(t *testing.T) {
+	backend := newFakeBackend()
+	in := newTestInput(backend)
+
+	var order []string
+	in.Use(
+		func(e Event) (Event, bool) {
+			order = append(order, "first")
+			if e.Key == KeyW {
+				e.Key = KeyUp
+			}
+			return e, true
+		},
+		nil,
+		func(e Event) (Event, bool) {
+			order = append(order, "second")
+			return e, e.Key != KeyQ
+		},
+	)
+	in.UseExpander(func(e Event, emit func(Event)) {
+		emit(e)
+		if e.Key == KeyUp {
+			emit(Event{Key: KeyTab, Pressed: true})
+		}
+	})
+	if err := in.Start(); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	defer in.Stop()
+
+	backend.events <- Event{Key: KeyQ, Pressed: true}
+	backend.events <- Event{Key: KeyW, Pressed: true}
+
+	for _, want := range []Key{KeyUp, KeyTab} {
+		event, err := in.PollTimeout(time.Second)
+		if err != nil || event.Key != want {
+			t.Fatalf("PollTimeout() = (%v, %v), want %v", event.Key, err, want)
+		}
+	}
+	if in.IsPressed(KeyW) || in.IsPressed(KeyQ) || !in.IsPressed(KeyUp) {
+		t.Error("key state does not reflect middleware output")
+	}
+	if len(order) != 4 || order[0] != "first" || order[1] != "second" {
+		t.Errorf("middleware call order = %v, want first, second per event", order)
+	}
+}
+
+// TestMiddlewarePanic validates that a panic in middleware restores the
+// terminal before it is re-raised.
+func TestMiddlewarePanic(t *testing.T) {
+	panics := make(chan any, 1)
+	defer func(orig func(any)) { capturePanic = orig }(capturePanic)
+	capturePanic = func(r any) { panics <- r }
+
+	backend := newFakeBackend()
+	in := newTestInput(backend)
+	in.Use(func(Event) (Event, bool) { panic("boom") })
+	if err := in.Start(); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	defer in.Stop()
+
+	backend.events <- Event{Key: KeyA, Pressed: true}
+	select {
+	case r := <-panics:
+		if r != "boom" {
+			t.Errorf("re-raised %v, want the original panic", r)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("middleware panic was not re-raised")
+	}
+	if got := backend.resetCount(); got != 1 {
+		t.Errorf("terminal reset %d times before the panic, want 1", got)
+	}
+}
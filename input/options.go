@@ -0,0 +1,322 @@
+package input
+
+import (
+	"os"
+	"time"
+)
+
+// Option configures an Input created by New or a SequenceParser created
+// by NewSequenceParser. Options that do not apply to the value being
+// constructed are ignored.
+type Option func(*config)
+
+// config holds the settings collected from Options.
+type config struct {
+	// graphemeClusters groups runes into extended grapheme clusters.
+	graphemeClusters bool
+
+	// c1Controls decodes 8-bit C1 control bytes (0x9b CSI, 0x8f SS3).
+	c1Controls bool
+
+	// highBitMeta treats bytes with the high bit set as Alt+byte.
+	highBitMeta bool
+
+	// charset decodes text bytes; nil means UTF-8.
+	charset Charset
+
+	// bufferSize is the capacity of the event channel.
+	bufferSize int
+
+	// file is the terminal input is read from; nil means os.Stdin.
+	file *os.File
+
+	// ttyPath is the terminal opened when stdin is not a terminal; empty
+	// means /dev/tty.
+	ttyPath string
+
+	// escapeTimeout is how long to wait for the rest of a partial
+	// escape sequence before decoding it as it stands.
+	escapeTimeout time.Duration
+
+	// protocols are the terminal input protocols enabled by Init.
+	protocols []Protocol
+
+	// clock timestamps events; nil means the system clock.
+	clock Clock
+
+	// overflow is what to do when the event queue is full.
+	overflow OverflowPolicy
+
+	// jobControl suspends the process on Ctrl+Z.
+	jobControl bool
+
+	// signalGuard restores the terminal on terminating signals.
+	signalGuard bool
+
+	// inferReleases synthesizes key-ups after releaseDelay without a
+	// repeat, or releaseInterval between repeats.
+	inferReleases   bool
+	releaseDelay    time.Duration
+	releaseInterval time.Duration
+
+	// rawBytes attaches the bytes each event was decoded from.
+	rawBytes bool
+
+	// repeatDelay and repeatInterval are the keyboard's autorepeat timing;
+	// zero means learn it.
+	repeatDelay    time.Duration
+	repeatInterval time.Duration
+}
+
+// Defaults used when the corresponding option is not given.
+const (
+	defaultBufferSize    = 100
+	defaultEscapeTimeout = 50 * time.Millisecond
+)
+
+// newConfig returns the default configuration with opts applied in order.
+func newConfig(opts []Option) config {
+	cfg := config{
+		bufferSize:      defaultBufferSize,
+		escapeTimeout:   defaultEscapeTimeout,
+		releaseDelay:    defaultReleaseDelay,
+		releaseInterval: defaultReleaseInterval,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+	return cfg
+}
+
+// now returns the current time from the configured clock.
+func (c *config) now() time.Time {
+	if c.clock == nil {
+		return time.Now()
+	}
+	return c.clock.Now()
+}
+
+// WithGraphemeClusters groups text input into extended grapheme clusters
+// (Unicode UAX #29). A character built from several code points - "é" typed
+// as e + U+0301, ZWJ emoji sequences, regional-indicator flags - is
+// delivered as a single event whose Text field holds the full cluster and
+// whose Rune and Key describe its first code point.
+//
+// Without this option each code point is delivered as a separate event.
+func WithGraphemeClusters() Option {
+	return func(c *config) {
+		c.graphemeClusters = true
+	}
+}
+
+// WithC1Controls decodes 8-bit C1 control introducers, as sent by xterm
+// with 8-bit controls enabled and by some serial terminals: 0x9b is treated
+// as CSI (ESC [) and 0x8f as SS3 (ESC O), so "0x9b A" is the Up arrow.
+// Other C1 bytes are reported as KeyUnknown.
+//
+// C1 bytes never start a valid UTF-8 character, so this option can be
+// combined with UTF-8 text input.
+func WithC1Controls() Option {
+	return func(c *config) {
+		c.c1Controls = true
+	}
+}
+
+// WithHighBitMeta decodes Alt (Meta) sent by setting the high bit of the
+// key byte instead of prefixing it with ESC, as xterm does with
+// eightBitInput and metaSendsEscape disabled: 0xe1 is Alt+a and 0x83 is
+// Alt+Ctrl+C.
+//
+// Every byte 0x80-0xff is a Meta key in this mode, so non-ASCII text cannot
+// be decoded. When combined with WithC1Controls, 0x9b and 0x8f are decoded
+// as C1 introducers rather than Meta keys.
+func WithHighBitMeta() Option {
+	return func(c *config) {
+		c.highBitMeta = true
+	}
+}
+
+// WithCharset decodes text input with cs instead of UTF-8, for terminals
+// running a legacy locale such as Latin-1. Escape sequences and control
+// characters are unaffected. Grapheme clustering (WithGraphemeClusters)
+// only applies to UTF-8 input.
+func WithCharset(cs Charset) Option {
+	return func(c *config) {
+		c.charset = cs
+	}
+}
+
+// WithLocaleCharset decodes text input with the charset named by the
+// LC_ALL, LC_CTYPE or LANG environment variables (see CharsetFromLocale).
+// Only UTF-8, Latin-1 and Windows-1252 are built in: other codesets, such
+// as Shift_JIS or GBK, must be registered with RegisterCharset before New
+// is called, or text is decoded as UTF-8. LocaleCharset reports that case.
+func WithLocaleCharset() Option {
+	return func(c *config) {
+		c.charset = CharsetFromLocale()
+	}
+}
+
+// WithBufferSize sets the capacity of the event queue between the capture
+// goroutine and Poll/Next (default 100). A size of 0 makes the queue
+// unbuffered, so each event is handed over directly. Negative sizes are
+// ignored.
+func WithBufferSize(n int) Option {
+	return func(c *config) {
+		if n >= 0 {
+			c.bufferSize = n
+		}
+	}
+}
+
+// WithFile reads input from f instead of os.Stdin, for example a
+// terminal device chosen by the caller. Protocol
+// enable/disable sequences (WithProtocols) are written to f as well, so
+// it should be opened read-write. The caller keeps ownership of f and
+// closes it after Stop.
+func WithFile(f *os.File) Option {
+	return func(c *config) {
+		c.file = f
+	}
+}
+
+// WithTTYPath sets the terminal device opened for keyboard input when
+// stdin is not a terminal, as in `producer | yourtool` (default /dev/tty).
+// The device is opened by Start and closed by Stop. It is not used when
+// WithFile is given.
+func WithTTYPath(path string) Option {
+	return func(c *config) {
+		c.ttyPath = path
+	}
+}
+
+// WithEscapeTimeout sets how long to wait for the rest of an escape
+// sequence before decoding what has arrived (default 50ms). A lone ESC is
+// reported as the Escape key, and ESC followed by a key as Alt plus that
+// key, only after this delay. Raise it for slow links such as SSH over
+// high-latency networks; lower it for snappier Escape handling. Values
+// <= 0 are ignored.
+func WithEscapeTimeout(d time.Duration) Option {
+	return func(c *config) {
+		if d > 0 {
+			c.escapeTimeout = d
+		}
+	}
+}
+
+// WithProtocols enables terminal input protocols while the input system is
+// running. Start writes the enable sequence of each protocol to the
+// terminal and Stop writes the matching disable sequence. Repeated calls
+// accumulate.
+func WithProtocols(protocols ...Protocol) Option {
+	return func(c *config) {
+		c.protocols = append(c.protocols, protocols...)
+	}
+}
+
+// WithClock sets the clock used to timestamp events. The default is the
+// system clock; tests and replay tools can substitute a fake.
+func WithClock(clock Clock) Option {
+	return func(c *config) {
+		c.clock = clock
+	}
+}
+
+// WithOverflowPolicy sets what happens when the event queue is full because
+// Poll/Next are not called often enough (default OverflowBlock). Events
+// lost to the policy are counted by Input.OverflowStats.
+//
+// No policy stops reading the terminal, so key state stays current: with
+// OverflowBlock, events that do not fit are held in memory, up to 4096,
+// rather than left in the terminal's buffer, and later ones are dropped.
+func WithOverflowPolicy(p OverflowPolicy) Option {
+	return func(c *config) {
+		c.overflow = p
+	}
+}
+
+// WithJobControl makes Ctrl+Z suspend the program as it does in a shell.
+// Raw mode delivers Ctrl+Z as an ordinary key; with this option the input
+// system instead restores the terminal, disables any protocols and stops
+// the process group with SIGTSTP. When the shell continues the program
+// (fg), raw mode and protocols are re-enabled and an Event with Type
+// EventResume is delivered so the application can redraw.
+//
+// Job control is only available on Unix systems; elsewhere the option is
+// ignored and Ctrl+Z is delivered as KeyCtrlZ.
+func WithJobControl() Option {
+	return func(c *config) {
+		c.jobControl = true
+	}
+}
+
+// WithSignalGuard restores the terminal when the program receives SIGINT,
+// SIGTERM, SIGHUP or SIGQUIT while the input system is running. The saved
+// terminal state is restored, enabled protocols and common application
+// modes (mouse reporting, bracketed paste, kitty keyboard) are turned off,
+// and the signal is then re-raised with its default action, so the program
+// still terminates as it would have.
+//
+// Applications that handle these signals themselves should not use the
+// guard; they should call Stop from their own handler instead. Panics are
+// covered separately by RestoreOnPanic.
+func WithSignalGuard() Option {
+	return func(c *config) {
+		c.signalGuard = true
+	}
+}
+
+// WithReleaseInference synthesizes key-up events on terminals that only
+// report key presses, so IsPressed and GameInput.IsActionPressed return
+// false again once a key is let go. Holding a key produces a press, then
+// after the keyboard's repeat delay a stream of autorepeat presses, marked
+// Repeat (see WithRepeatTiming); a key-up with Synthetic set is delivered
+// when they stop. A key is released after delay without an autorepeat, or
+// after interval between autorepeats (defaults 700ms and 100ms). Set them
+// just above the system's keyboard repeat delay and interval; values <= 0
+// keep the defaults.
+//
+// A press of a held key that is not an autorepeat, such as a double tap,
+// is delivered after a synthesized key-up for the earlier press. Until the
+// repeat timing is learned, the first autorepeat of a held key is treated
+// this way too; WithRepeatTiming avoids that.
+//
+// Terminals repeat only the most recently pressed key, so a key held while
+// another is pressed is reported released. Inference turns itself off if
+// the terminal reports real key-ups (see ProtocolWin32Input).
+func WithReleaseInference(delay, interval time.Duration) Option {
+	return func(c *config) {
+		c.inferReleases = true
+		if delay > 0 {
+			c.releaseDelay = delay
+		}
+		if interval > 0 {
+			c.releaseInterval = interval
+		}
+	}
+}
+
+// WithRepeatTiming sets the keyboard's autorepeat delay and interval, used
+// to mark autorepeated presses with Event.Repeat on terminals that report
+// them as ordinary presses. A press of the same key that follows the first
+// press after about delay, or a repeat after about interval, is a repeat.
+// Values <= 0 are learned from runs of presses too fast to be typed by
+// hand, which is the default; until then only such presses are marked.
+func WithRepeatTiming(delay, interval time.Duration) Option {
+	return func(c *config) {
+		c.repeatDelay = max(delay, 0)
+		c.repeatInterval = max(interval, 0)
+	}
+}
+
+// WithRawBytes attaches to every event the exact bytes the terminal sent
+// for it, in Event.Raw, so reports like "key X doesn't work" can be
+// diagnosed. Each event then costs an allocation. Without the option only
+// unrecognized input carries Raw.
+func WithRawBytes() Option {
+	return func(c *config) {
+		c.rawBytes = true
+	}
+}
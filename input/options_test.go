@@ -0,0 +1,96 @@
+package input
+
+import (
+	"testing"
+	"time"
+)
+
+// TestNewConfigDefaults validates that New() without options keeps the
+// historical defaults.
+func TestNewConfigDefaults(t *testing.T) {
+	cfg := newConfig(nil)
+	if cfg.bufferSize != defaultBufferSize {
+		t.Errorf("bufferSize = %d, want %d", cfg.bufferSize, defaultBufferSize)
+	}
+	if cfg.escapeTimeout != defaultEscapeTimeout {
+		t.Errorf("escapeTimeout = %v, want %v", cfg.escapeTimeout, defaultEscapeTimeout)
+	}
+	if cfg.file != nil || cfg.clock != nil || len(cfg.protocols) != 0 {
+		t.Errorf("unexpected non-default config: %+v", cfg)
+	}
+
+	in := New().(*inputImpl)
+	if got := cap(in.events); got != defaultBufferSize {
+		t.Errorf("event queue capacity = %d, want %d", got, defaultBufferSize)
+	}
+}
+
+// TestOptions validates that each option is applied to the configuration,
+// and that out-of-range values are ignored.
+func TestOptions(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  []Option
+		check func(config) bool
+	}{
+		{"buffer size", []Option{WithBufferSize(8)}, func(c config) bool { return c.bufferSize == 8 }},
+		{"unbuffered", []Option{WithBufferSize(0)}, func(c config) bool { return c.bufferSize == 0 }},
+		{"negative buffer size ignored", []Option{WithBufferSize(-1)}, func(c config) bool { return c.bufferSize == defaultBufferSize }},
+		{"escape timeout", []Option{WithEscapeTimeout(time.Second)}, func(c config) bool { return c.escapeTimeout == time.Second }},
+		{"zero escape timeout ignored", []Option{WithEscapeTimeout(0)}, func(c config) bool { return c.escapeTimeout == defaultEscapeTimeout }},
+		{"protocols accumulate", []Option{WithProtocols(ProtocolWin32Input), WithProtocols(ProtocolApplicationCursor)}, func(c config) bool {
+			return len(c.protocols) == 2 && c.protocols[0] == ProtocolWin32Input && c.protocols[1] == ProtocolApplicationCursor
+		}},
+		{"release inference", []Option{WithReleaseInference(time.Second, 0)}, func(c config) bool {
+			return c.inferReleases && c.releaseDelay == time.Second && c.releaseInterval == defaultReleaseInterval
+		}},
+		{"repeat timing", []Option{WithRepeatTiming(500*time.Millisecond, -1)}, func(c config) bool {
+			return c.repeatDelay == 500*time.Millisecond && c.repeatInterval == 0
+		}},
+		{"raw bytes", []Option{WithRawBytes()}, func(c config) bool { return c.rawBytes }},
+		{"nil option skipped", []Option{nil}, func(c config) bool { return c.bufferSize == defaultBufferSize }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if cfg := newConfig(tt.opts); !tt.check(cfg) {
+				t.Errorf("unexpected config: %+v", cfg)
+			}
+		})
+	}
+
+	in := New(WithBufferSize(3)).(*inputImpl)
+	if got := cap(in.events); got != 3 {
+		t.Errorf("event queue capacity = %d, want 3", got)
+	}
+}
+
+// TestWithClock validates that parsed events are stamped by the configured
+// clock.
+func TestWithClock(t *testing.T) {
+	fixed := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	p := NewSequenceParser(WithClock(ClockFunc(func() time.Time { return fixed })))
+
+	for _, seq := range []string{"a", "\x1b[A", "\x1bx", "é"} {
+		event, err := p.Parse([]byte(seq))
+		if err != nil {
+			t.Fatalf("Parse(%q) error: %v", seq, err)
+		}
+		if !event.Timestamp.Equal(fixed) {
+			t.Errorf("Parse(%q).Timestamp = %v, want %v", seq, event.Timestamp, fixed)
+		}
+	}
+}
+
+// TestProtocolSequences validates that every protocol has matching enable
+// and disable sequences.
+func TestProtocolSequences(t *testing.T) {
+	for _, p := range []Protocol{ProtocolApplicationCursor, ProtocolApplicationKeypad, ProtocolWin32Input} {
+		if p.enableSequence() == "" || p.disableSequence() == "" {
+			t.Errorf("%v: missing enable or disable sequence", p)
+		}
+		if p.enableSequence() == p.disableSequence() {
+			t.Errorf("%v: enable and disable sequences are identical", p)
+		}
+	}
+}
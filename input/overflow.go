@@ -0,0 +1,62 @@
+package input
+
+// OverflowPolicy selects what the capture goroutine does with a new event
+// when the event queue is full because the consumer is falling behind.
+type OverflowPolicy uint8
+
+const (
+	// OverflowBlock holds new events back, in order, until the consumer
+	// makes room. The terminal is still read meanwhile, so key state and
+	// subscriptions stay current; beyond 4096 held-back events new ones are
+	// dropped, as a full terminal buffer would drop them. This is the
+	// default.
+	OverflowBlock OverflowPolicy = iota
+
+	// OverflowDropNewest discards the new event, keeping the queued ones.
+	OverflowDropNewest
+
+	// OverflowDropOldest discards the oldest queued event to make room, so
+	// the consumer always sees the most recent input.
+	OverflowDropOldest
+
+	// OverflowCoalesce discards the new event if it repeats the most
+	// recently queued event (same key, rune, modifiers and press state),
+	// which is typical of held keys autorepeating. Other events are held
+	// back as with OverflowBlock.
+	OverflowCoalesce
+)
+
+// String returns the name of the policy.
+func (p OverflowPolicy) String() string {
+	switch p {
+	case OverflowBlock:
+		return "Block"
+	case OverflowDropNewest:
+		return "DropNewest"
+	case OverflowDropOldest:
+		return "DropOldest"
+	case OverflowCoalesce:
+		return "Coalesce"
+	default:
+		return "Unknown"
+	}
+}
+
+// OverflowStats counts events lost to the overflow policy since the Input
+// was created.
+type OverflowStats struct {
+	// Dropped is the number of events discarded by OverflowDropNewest or
+	// OverflowDropOldest, and by OverflowBlock or OverflowCoalesce once
+	// 4096 events are held back.
+	Dropped uint64
+
+	// Coalesced is the number of repeated events merged by OverflowCoalesce.
+	Coalesced uint64
+}
+
+// sameKeyEvent reports whether a and b describe the same key action,
+// ignoring timestamps and the repeat flag.
+func sameKeyEvent(a, b Event) bool {
+	return a.Key == b.Key && a.Rune == b.Rune && a.Modifiers == b.Modifiers &&
+		a.Pressed == b.Pressed && a.Text == b.Text
+}
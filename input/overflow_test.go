@@ -0,0 +1,152 @@
+package input
+
+import (
+	"testing"
+	"time"
+)
+
+// fillQueue starts in with a queue of size events and feeds keys through
+// the backend, waiting until the capture goroutine has handled them all.
+func fillQueue(t *testing.T, policy OverflowPolicy, size int, keys []Key) *inputImpl {
+	t.Helper()
+	backend := newFakeBackend()
+	in := New(WithBufferSize(size), WithOverflowPolicy(policy)).(*inputImpl)
+	in.backend = backend
+	if err := in.Start(); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	for _, k := range keys {
+		backend.events <- Event{Key: k, Pressed: true}
+	}
+	// Wait for the capture goroutine to take every event from the backend
+	deadline := time.Now().Add(time.Second)
+	for len(backend.events) > 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(10 * time.Millisecond)
+	return in
+}
+
+// drainKeys returns the keys of all queued events.
+func drainKeys(in *inputImpl) []Key {
+	var keys []Key
+	for e := in.Next(); e != nil; e = in.Next() {
+		keys = append(keys, e.Key)
+	}
+	return keys
+}
+
+// TestOverflowPolicies validates which events each policy keeps when the
+// queue overflows, and that losses are counted.
+func TestOverflowPolicies(t *testing.T) {
+	tests := []struct {
+		name          string
+		policy        OverflowPolicy
+		keys          []Key
+		wantKeys      []Key
+		wantDropped   uint64
+		wantCoalesced uint64
+	}{
+		{
+			name:        "drop newest",
+			policy:      OverflowDropNewest,
+			keys:        []Key{KeyA, KeyB, KeyC, KeyD},
+			wantKeys:    []Key{KeyA, KeyB},
+			wantDropped: 2,
+		},
+		{
+			name:        "drop oldest",
+			policy:      OverflowDropOldest,
+			keys:        []Key{KeyA, KeyB, KeyC, KeyD},
+			wantKeys:    []Key{KeyC, KeyD},
+			wantDropped: 2,
+		},
+		{
+			name:          "coalesce repeats",
+			policy:        OverflowCoalesce,
+			keys:          []Key{KeyA, KeyB, KeyB, KeyB},
+			wantKeys:      []Key{KeyA, KeyB},
+			wantCoalesced: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := fillQueue(t, tt.policy, 2, tt.keys)
+			defer in.Stop()
+
+			keys := drainKeys(in)
+			if len(keys) != len(tt.wantKeys) {
+				t.Fatalf("queued keys = %v, want %v", keys, tt.wantKeys)
+			}
+			for i := range keys {
+				if keys[i] != tt.wantKeys[i] {
+					t.Fatalf("queued keys = %v, want %v", keys, tt.wantKeys)
+				}
+			}
+
+			stats := in.OverflowStats()
+			if stats.Dropped != tt.wantDropped || stats.Coalesced != tt.wantCoalesced {
+				t.Errorf("OverflowStats() = %+v, want {Dropped:%d Coalesced:%d}",
+					stats, tt.wantDropped, tt.wantCoalesced)
+			}
+		})
+	}
+}
+
+// TestOverflowBlock validates that the default policy loses nothing: the
+// capture goroutine waits for room instead.
+func TestOverflowBlock(t *testing.T) {
+	backend := newFakeBackend()
+	in := New(WithBufferSize(1)).(*inputImpl)
+	in.backend = backend
+	if err := in.Start(); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	defer in.Stop()
+
+	for _, k := range []Key{KeyA, KeyB, KeyC} {
+		backend.events <- Event{Key: k, Pressed: true}
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	var keys []Key
+	for i := 0; i < 3; i++ {
+		event, ok := in.Poll()
+		if !ok {
+			t.Fatal("Poll() returned false")
+		}
+		keys = append(keys, event.Key)
+	}
+	if keys[0] != KeyA || keys[1] != KeyB || keys[2] != KeyC {
+		t.Errorf("keys = %v, want [A B C]", keys)
+	}
+	if stats := in.OverflowStats(); stats != (OverflowStats{}) {
+		t.Errorf("OverflowStats() = %+v, want zero", stats)
+	}
+}
+
+// TestOverflowBacklogLimit validates that events held back for a full queue
+// are bounded: beyond maxBacklog new events are dropped and counted.
+func TestOverflowBacklogLimit(t *testing.T) {
+	keys := make([]Key, 1+maxBacklog+3)
+	for i := range keys {
+		keys[i] = KeyA
+	}
+	keys[len(keys)-1] = KeyB
+	in := fillQueue(t, OverflowBlock, 1, keys)
+	defer in.Stop()
+
+	if stats := in.OverflowStats(); stats.Dropped != 3 {
+		t.Errorf("OverflowStats().Dropped = %d, want 3", stats.Dropped)
+	}
+	for n := 0; n < 1+maxBacklog; n++ {
+		event, err := in.PollTimeout(time.Second)
+		if err != nil {
+			t.Fatalf("PollTimeout() after %d events error = %v", n, err)
+		}
+		if event.Key != KeyA {
+			t.Fatalf("event %d: Key = %v, want KeyA", n, event.Key)
+		}
+	}
+}
@@ -2,218 +2,462 @@ package input
 
 import (
 	"fmt"
-	"time"
 	"unicode/utf8"
 )
 
-// SequenceNode represents a node in the escape sequence trie.
-// Each node can either be a leaf (with a Key value) or an internal
-// node with children for continued parsing.
-type SequenceNode struct {
-	key      Key
-	modifier Modifier
-	children map[byte]*SequenceNode
+// SequenceParser parses terminal input into normalized Events.
+// Sequence boundaries are found by an ECMA-48 input state machine (see
+// scan); complete sequences are decoded from their introducer, parameters
+// and final byte, so modified keys such as Ctrl+Up (ESC [ 1 ; 5 A) need no
+// table entries of their own.
+type SequenceParser struct {
+	cfg config
+
+	// resume is the state in which scanning continues while the remainder
+	// of a sequence longer than maxSequenceLength is being discarded.
+	resume vtState
+
+	// win32Held records which virtual keys are down in win32 input mode,
+	// so autorepeated key-downs can be flagged as repeats.
+	win32Held [256]bool
+
+	// win32Surrogate holds the high half of a UTF-16 surrogate pair
+	// until the event carrying the low half arrives.
+	win32Surrogate rune
 }
 
-// SequenceParser parses terminal escape sequences into normalized Events.
-// It uses a trie structure for efficient multi-byte sequence recognition.
-type SequenceParser struct {
-	root *SequenceNode
+// maxSequenceLength bounds how many bytes of a sequence are buffered.
+// Longer sequences are reported (or, for control strings, discarded)
+// in pieces without losing track of where they end.
+const maxSequenceLength = 64
+
+// maxCSIParams is the number of CSI parameters decoded; further
+// parameters are ignored.
+const maxCSIParams = 4
+
+// NewSequenceParser creates a new parser for terminal input.
+// Options such as WithGraphemeClusters change how text is decoded;
+// options unrelated to parsing are ignored.
+func NewSequenceParser(opts ...Option) *SequenceParser {
+	return newSequenceParser(newConfig(opts))
 }
 
-// NewSequenceParser creates a new parser initialized with common
-// terminal escape sequences.
-func NewSequenceParser() *SequenceParser {
-	p := &SequenceParser{
-		root: &SequenceNode{
-			children: make(map[byte]*SequenceNode),
-		},
-	}
-	p.buildTrie()
-	return p
+// newSequenceParser creates a parser using an already-built configuration.
+func newSequenceParser(cfg config) *SequenceParser {
+	return &SequenceParser{cfg: cfg}
 }
 
-// Parse converts a byte sequence into an Event.
-// It recognizes escape sequences, control characters, and printable characters.
+// reset discards state carried between sequences, for a new input session.
+func (p *SequenceParser) reset() {
+	p.resume = vtGround
+	p.win32Held = [256]bool{}
+	p.win32Surrogate = 0
+}
+
+// Parse converts a single sequence into an Event.
+// It recognizes escape sequences, control characters, and printable
+// characters. seq is expected to hold exactly one sequence; unrecognized
+// or malformed sequences produce an Event with Key=KeyUnknown.
 func (p *SequenceParser) Parse(seq []byte) (Event, error) {
 	if len(seq) == 0 {
 		return Event{}, fmt.Errorf("empty sequence")
 	}
 
-	event := Event{
-		Timestamp: time.Now(),
+	// 8-bit C1 introducers and high-bit Meta bytes are only recognized
+	// when enabled; otherwise bytes >= 0x80 are text
+	if seq[0] >= 0x80 && (p.cfg.c1Controls || p.cfg.highBitMeta) {
+		if event, ok, err := p.parseEightBit(seq); ok {
+			return event, err
+		}
+	}
+
+	// A grapheme cluster spanning several runes is reported as a single
+	// event for its first rune, carrying the whole cluster in Text
+	if p.cfg.graphemeClusters && p.charset() == UTF8 && isTextByte(seq[0]) {
+		if _, size := utf8.DecodeRune(seq); size < len(seq) {
+			event, err := p.Parse(seq[:size])
+			if err != nil {
+				return event, err
+			}
+			event.Text = string(seq)
+			return event, nil
+		}
+	}
+
+	event := p.newEvent()
+
+	switch {
+	case seq[0] == ctrlESC:
+		return p.parseEscape(seq, event)
+	case seq[0] >= 0x80:
+		return p.parseText(seq, event)
+	case len(seq) == 1:
+		return p.parseByte(seq[0], event), nil
+	}
+
+	// Several bytes that do not form one sequence
+	event.Key = KeyUnknown
+	return event, nil
+}
+
+// newEvent returns a key-down event stamped with the configured clock.
+func (p *SequenceParser) newEvent() Event {
+	return Event{
+		Timestamp: p.cfg.now(),
 		Pressed:   true,
 		Repeat:    false,
 	}
+}
 
-	// Handle single-byte sequences
-	if len(seq) == 1 {
-		b := seq[0]
+// unknownEvent returns a key-down event for an unrecognized sequence.
+func (p *SequenceParser) unknownEvent() Event {
+	event := p.newEvent()
+	event.Key = KeyUnknown
+	return event
+}
 
+// parseByte decodes a single ASCII byte: a control character or a
+// printable character.
+func (p *SequenceParser) parseByte(b byte, event Event) Event {
+	switch {
+	case b == ctrlESC:
 		// Escape key (standalone ESC)
-		if b == 0x1b {
-			event.Key = KeyEscape
-			return event, nil
-		}
-
+		event.Key = KeyEscape
+	case b == 0x09:
+		// Tab
+		event.Key = KeyTab
+		event.Rune = '\t'
+	case b == 0x0d:
+		// Enter/Return
+		event.Key = KeyEnter
+		event.Rune = '\r'
+	case b >= 0x01 && b <= 0x1a:
 		// Control characters (Ctrl+A through Ctrl+Z)
-		if b >= 0x01 && b <= 0x1a {
-			event.Key = p.ctrlCharToKey(b)
-			event.Modifiers = ModCtrl
-			return event, nil
+		event.Key = p.ctrlCharToKey(b)
+		event.Modifiers = ModCtrl
+	case b == 0x7f:
+		// Backspace
+		event.Key = KeyBackspace
+	case b == 0x20:
+		// Space
+		event.Key = KeySpace
+		event.Rune = ' '
+		if p.cfg.graphemeClusters {
+			event.Text = " "
 		}
-
-		// Tab
-		if b == 0x09 {
-			event.Key = KeyTab
-			event.Rune = '\t'
-			return event, nil
+	case b > 0x20 && b <= 0x7e:
+		// Printable ASCII
+		event.Rune = rune(b)
+		event.Key = p.runeToKey(event.Rune)
+		if p.cfg.graphemeClusters {
+			event.Text = asciiStrings[b]
 		}
+	default:
+		// NUL and the remaining C0 controls
+		event.Key = KeyUnknown
+	}
+	return event
+}
 
-		// Enter/Return
-		if b == 0x0d {
-			event.Key = KeyEnter
-			event.Rune = '\r'
-			return event, nil
-		}
+// asciiStrings holds one-character strings for printable ASCII, so text
+// events for them do not allocate.
+var asciiStrings = func() (s [0x80]string) {
+	for b := 0x20; b < 0x7f; b++ {
+		s[b] = string(rune(b))
+	}
+	return s
+}()
 
-		// Backspace
-		if b == 0x7f || b == 0x08 {
-			event.Key = KeyBackspace
-			return event, nil
-		}
+// parseText decodes a character that starts with a byte >= 0x80 using the
+// configured charset (UTF-8 by default).
+func (p *SequenceParser) parseText(seq []byte, event Event) (Event, error) {
+	cs := p.charset()
 
-		// Space
-		if b == 0x20 {
-			event.Key = KeySpace
-			event.Rune = ' '
-			return event, nil
-		}
+	// Check for complete character
+	if !cs.FullRune(seq) {
+		return Event{}, errIncomplete
+	}
 
-		// Printable ASCII (single-byte UTF-8)
-		if b >= 0x20 && b <= 0x7e {
-			event.Rune = rune(b)
-			event.Key = p.runeToKey(event.Rune)
-			return event, nil
-		}
+	r, size := cs.DecodeRune(seq)
+	if r == utf8.RuneError && size == 1 || size != len(seq) {
+		// Invalid encoding, or more than one character
+		event.Key = KeyUnknown
+		event.Rune = utf8.RuneError
+		return event, nil
+	}
 
-		// Check if this is the start of a multi-byte UTF-8 character
-		// UTF-8 lead bytes: 0x80-0xFF
-		if b >= 0x80 {
-			// Multi-byte UTF-8 - fallthrough to UTF-8 handling below
-			// (this will be caught by the multi-byte sequence handler)
-		} else {
-			// Unknown single byte
-			event.Key = KeyUnknown
-			return event, nil
-		}
+	event.Rune = r
+	// Non-ASCII characters map to KeyUnknown
+	event.Key = KeyUnknown
+	if p.cfg.graphemeClusters {
+		event.Text = string(r)
 	}
+	return event, nil
+}
 
-	// UTF-8 multi-byte character handling
-	// Check if this is a valid UTF-8 sequence (not an escape sequence)
-	if len(seq) > 0 && seq[0] >= 0x80 && seq[0] != 0x1b {
-		// Check for complete UTF-8 sequence
-		if !utf8.FullRune(seq) {
-			return Event{}, fmt.Errorf("incomplete UTF-8 sequence")
-		}
+// parseEscape decodes a sequence starting with ESC.
+func (p *SequenceParser) parseEscape(seq []byte, event Event) (Event, error) {
+	if len(seq) == 1 {
+		event.Key = KeyEscape
+		return event, nil
+	}
 
-		r, size := utf8.DecodeRune(seq)
-		if r == utf8.RuneError && size == 1 {
-			// Invalid UTF-8 encoding
-			event.Key = KeyUnknown
-			event.Rune = utf8.RuneError
-			return event, nil
-		}
+	if len(seq) == 2 && seq[1] == ctrlESC {
+		// Alt+Escape
+		event.Key = KeyEscape
+		event.Modifiers = ModAlt
+		return event, nil
+	}
+
+	n, complete, _ := p.scanGround(seq)
+	if !complete && len(seq) == 2 {
+		// ESC followed by an introducer and nothing else (the escape
+		// timeout expired): Alt plus that key
+		return p.parseAlt(seq[1:], event)
+	}
+	if !complete || n != len(seq) {
+		// Incomplete, or trailing bytes after the sequence
+		event.Key = KeyUnknown
+		return event, nil
+	}
 
-		event.Rune = r
-		// Non-ASCII characters map to KeyUnknown
+	switch intro := seq[1]; {
+	case intro == '[':
+		return p.parseCSI(seq, event)
+	case intro == 'O':
+		return p.parseSS3(seq[2:], event), nil
+	case intro == ']' || intro == 'P' || intro == 'X' || intro == '^' || intro == '_':
+		// Control strings are terminal replies, not keys
+		return Event{}, errNoEvent
+	case intro >= 0x20 && intro <= 0x2f && len(seq) > 2:
+		// nF escape sequences (ESC SP F, ESC ( B, ...) are not keys
 		event.Key = KeyUnknown
 		return event, nil
 	}
 
-	// Multi-byte sequences - check trie
-	node := p.root
-	for _, b := range seq {
-		if node.children == nil {
+	// Alt + key (ESC prefix)
+	return p.parseAlt(seq[1:], event)
+}
+
+// parseAlt decodes key, which followed an ESC prefix, and adds ModAlt.
+func (p *SequenceParser) parseAlt(key []byte, event Event) (Event, error) {
+	alt, err := p.Parse(key)
+	if err != nil {
+		return event, err
+	}
+	alt.Modifiers |= ModAlt
+	alt.Timestamp = event.Timestamp
+	return alt, nil
+}
+
+// csiParams holds the decoded numeric parameters of a CSI or SS3 sequence.
+type csiParams struct {
+	values  [maxCSIParams]int
+	count   int
+	private bool // starts with a private marker (< = > ?)
+}
+
+// parseParams decodes semicolon-separated numeric parameters. Colon
+// sub-parameters are skipped. Values are clamped to avoid overflow.
+func parseParams(b []byte) csiParams {
+	var ps csiParams
+	if len(b) == 0 {
+		return ps
+	}
+	if b[0] >= 0x3c && b[0] <= 0x3f {
+		ps.private = true
+		return ps
+	}
+
+	ps.count = 1
+	sub := false
+	for _, c := range b {
+		switch {
+		case c == ';':
+			ps.count++
+			sub = false
+		case c == ':':
+			sub = true
+		case c >= '0' && c <= '9' && !sub && ps.count <= maxCSIParams:
+			v := &ps.values[ps.count-1]
+			if *v < 1<<16 {
+				*v = *v*10 + int(c-'0')
+			}
+		}
+	}
+	return ps
+}
+
+// modifiers decodes the xterm modifier parameter at index i: the value
+// minus one is a bitmask of Shift (1), Alt (2), Ctrl (4) and Meta (8).
+func (ps csiParams) modifiers(i int) Modifier {
+	if i >= ps.count || i >= maxCSIParams || ps.values[i] < 2 {
+		return ModNone
+	}
+	bits := ps.values[i] - 1
+	var mods Modifier
+	if bits&1 != 0 {
+		mods |= ModShift
+	}
+	if bits&(2|8) != 0 {
+		mods |= ModAlt
+	}
+	if bits&4 != 0 {
+		mods |= ModCtrl
+	}
+	return mods
+}
+
+// parseCSI decodes a complete CSI sequence (ESC [ params intermediates final).
+func (p *SequenceParser) parseCSI(seq []byte, event Event) (Event, error) {
+	// Win32 input mode (CSI Vk;Sc;Uc;Kd;Cs;Rc _)
+	if isWin32InputSequence(seq) {
+		return p.parseWin32Input(seq, event)
+	}
+
+	body := seq[2:]
+	final := body[len(body)-1]
+	params := body[:len(body)-1]
+
+	// Linux console function keys: ESC [ [ A through ESC [ [ E
+	if len(body) == 2 && body[0] == '[' {
+		if final >= 'A' && final <= 'E' {
+			event.Key = Key(int(KeyF1) + int(final-'A'))
+		} else {
 			event.Key = KeyUnknown
-			return event, nil
 		}
+		return event, nil
+	}
 
-		next, ok := node.children[b]
-		if !ok {
+	for _, c := range params {
+		if c < 0x30 || c > 0x3f {
+			// Intermediate bytes: not a key sequence
 			event.Key = KeyUnknown
 			return event, nil
 		}
-
-		node = next
 	}
 
-	// Check if we landed on a leaf node
-	if node.key != KeyUnknown {
-		event.Key = node.key
-		event.Modifiers = node.modifier
+	ps := parseParams(params)
+	if ps.private {
+		// Private sequences (mouse reports, device attributes) are not keys
+		event.Key = KeyUnknown
 		return event, nil
 	}
 
-	// Unknown sequence
-	event.Key = KeyUnknown
+	switch final {
+	case '~':
+		event.Key = tildeKey(ps.values[0])
+		event.Modifiers = ps.modifiers(1)
+	case 'Z':
+		// Back-tab
+		event.Key = KeyTab
+		event.Modifiers = ModShift
+	default:
+		event.Key = finalKey(final)
+		event.Modifiers = ps.modifiers(1)
+	}
 	return event, nil
 }
 
-// buildTrie constructs the escape sequence trie with common terminal sequences.
-func (p *SequenceParser) buildTrie() {
-	// CSI sequences (ESC [)
-	p.addSequence([]byte{0x1b, '[', 'A'}, KeyUp, ModNone)
-	p.addSequence([]byte{0x1b, '[', 'B'}, KeyDown, ModNone)
-	p.addSequence([]byte{0x1b, '[', 'C'}, KeyRight, ModNone)
-	p.addSequence([]byte{0x1b, '[', 'D'}, KeyLeft, ModNone)
-
-	p.addSequence([]byte{0x1b, '[', 'H'}, KeyHome, ModNone)
-	p.addSequence([]byte{0x1b, '[', 'F'}, KeyEnd, ModNone)
-
-	p.addSequence([]byte{0x1b, '[', '2', '~'}, KeyInsert, ModNone)
-	p.addSequence([]byte{0x1b, '[', '3', '~'}, KeyDelete, ModNone)
-	p.addSequence([]byte{0x1b, '[', '5', '~'}, KeyPageUp, ModNone)
-	p.addSequence([]byte{0x1b, '[', '6', '~'}, KeyPageDown, ModNone)
-
-	// Function keys (SS3 sequences: ESC O)
-	p.addSequence([]byte{0x1b, 'O', 'P'}, KeyF1, ModNone)
-	p.addSequence([]byte{0x1b, 'O', 'Q'}, KeyF2, ModNone)
-	p.addSequence([]byte{0x1b, 'O', 'R'}, KeyF3, ModNone)
-	p.addSequence([]byte{0x1b, 'O', 'S'}, KeyF4, ModNone)
-
-	// Function keys (CSI sequences: ESC [)
-	p.addSequence([]byte{0x1b, '[', '1', '5', '~'}, KeyF5, ModNone)
-	p.addSequence([]byte{0x1b, '[', '1', '7', '~'}, KeyF6, ModNone)
-	p.addSequence([]byte{0x1b, '[', '1', '8', '~'}, KeyF7, ModNone)
-	p.addSequence([]byte{0x1b, '[', '1', '9', '~'}, KeyF8, ModNone)
-	p.addSequence([]byte{0x1b, '[', '2', '0', '~'}, KeyF9, ModNone)
-	p.addSequence([]byte{0x1b, '[', '2', '1', '~'}, KeyF10, ModNone)
-	p.addSequence([]byte{0x1b, '[', '2', '3', '~'}, KeyF11, ModNone)
-	p.addSequence([]byte{0x1b, '[', '2', '4', '~'}, KeyF12, ModNone)
+// parseSS3 decodes the body of an SS3 sequence (ESC O [params] final).
+func (p *SequenceParser) parseSS3(body []byte, event Event) Event {
+	final := body[len(body)-1]
+	ps := parseParams(body[:len(body)-1])
+
+	// A single parameter is a modifier (ESC O 2 P); two are 1;modifier
+	if ps.count == 1 {
+		event.Modifiers = ps.modifiers(0)
+	} else {
+		event.Modifiers = ps.modifiers(1)
+	}
+
+	switch {
+	case final == 'M':
+		// Keypad Enter
+		event.Key = KeyEnter
+		event.Rune = '\r'
+	case final >= 'p' && final <= 'y':
+		// Application keypad digits
+		event.Rune = rune('0' + final - 'p')
+		event.Key = p.runeToKey(event.Rune)
+	case final >= 'j' && final <= 'o' && final != 'l':
+		// Application keypad operators: * + , - . /
+		event.Rune = rune('*' + final - 'j')
+		event.Key = KeyUnknown
+	default:
+		event.Key = finalKey(final)
+	}
+	return event
 }
 
-// addSequence adds a byte sequence to the trie with the given key and modifier.
-func (p *SequenceParser) addSequence(seq []byte, key Key, mod Modifier) {
-	node := p.root
-	for _, b := range seq {
-		if node.children == nil {
-			node.children = make(map[byte]*SequenceNode)
-		}
+// finalKey maps the final byte of a CSI or SS3 cursor/function key
+// sequence to a Key.
+func finalKey(final byte) Key {
+	switch final {
+	case 'A':
+		return KeyUp
+	case 'B':
+		return KeyDown
+	case 'C':
+		return KeyRight
+	case 'D':
+		return KeyLeft
+	case 'H':
+		return KeyHome
+	case 'F':
+		return KeyEnd
+	case 'P':
+		return KeyF1
+	case 'Q':
+		return KeyF2
+	case 'R':
+		return KeyF3
+	case 'S':
+		return KeyF4
+	default:
+		return KeyUnknown
+	}
+}
 
-		if node.children[b] == nil {
-			node.children[b] = &SequenceNode{
-				key:      KeyUnknown,
-				children: make(map[byte]*SequenceNode),
-			}
-		}
+// tildeKey maps the first parameter of a CSI ... ~ sequence to a Key.
+// Both the VT220/xterm and rxvt numbering are recognized.
+func tildeKey(code int) Key {
+	switch code {
+	case 1, 7:
+		return KeyHome
+	case 2:
+		return KeyInsert
+	case 3:
+		return KeyDelete
+	case 4, 8:
+		return KeyEnd
+	case 5:
+		return KeyPageUp
+	case 6:
+		return KeyPageDown
+	case 11, 12, 13, 14, 15:
+		return Key(int(KeyF1) + code - 11)
+	case 17, 18, 19, 20, 21:
+		return Key(int(KeyF6) + code - 17)
+	case 23, 24:
+		return Key(int(KeyF11) + code - 23)
+	default:
+		return KeyUnknown
+	}
+}
 
-		node = node.children[b]
+// charset returns the Charset used to decode text input.
+func (p *SequenceParser) charset() Charset {
+	if p.cfg.charset == nil {
+		return UTF8
 	}
+	return p.cfg.charset
+}
 
-	// Set the key at the leaf node
-	node.key = key
-	node.modifier = mod
+// isTextByte reports whether b can start a printable character, as opposed
+// to a control character or escape sequence.
+func isTextByte(b byte) bool {
+	return b >= 0x20 && b != 0x7f && b != 0x1b
 }
 
 // ctrlCharToKey converts a control character byte to its corresponding Key.
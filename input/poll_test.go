@@ -0,0 +1,274 @@
+package input
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestPollContext validates that PollContext returns events, honors
+// cancellation and deadlines, and reports shutdown.
+func TestPollContext(t *testing.T) {
+	backend := newFakeBackend()
+	in := newTestInput(backend)
+	if err := in.Start(); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	defer in.Stop()
+
+	backend.events <- Event{Key: KeyA, Pressed: true}
+	event, err := in.PollContext(context.Background())
+	if err != nil || event.Key != KeyA {
+		t.Fatalf("PollContext() = (%v, %v), want (KeyA, nil)", event.Key, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := in.PollContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("PollContext() with deadline error = %v, want DeadlineExceeded", err)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	if _, err := in.PollContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("PollContext() with cancelled context error = %v, want Canceled", err)
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		in.Stop()
+	}()
+	if _, err := in.PollContext(context.Background()); !errors.Is(err, ErrInputClosed) {
+		t.Errorf("PollContext() after Stop error = %v, want ErrInputClosed", err)
+	}
+}
+
+// TestPollTimeout validates that PollTimeout returns events, gives up after
+// the timeout, and does not block for non-positive durations.
+func TestPollTimeout(t *testing.T) {
+	backend := newFakeBackend()
+	in := newTestInput(backend)
+	if err := in.Start(); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	defer in.Stop()
+
+	backend.events <- Event{Key: KeyB, Pressed: true}
+	event, err := in.PollTimeout(time.Second)
+	if err != nil || event.Key != KeyB {
+		t.Fatalf("PollTimeout() = (%v, %v), want (KeyB, nil)", event.Key, err)
+	}
+	if !in.IsPressed(KeyB) {
+		t.Error("PollTimeout() should update key state")
+	}
+
+	start := time.Now()
+	if _, err := in.PollTimeout(20 * time.Millisecond); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("PollTimeout() error = %v, want DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("PollTimeout() returned after %v, want at least 20ms", elapsed)
+	}
+
+	if _, err := in.PollTimeout(0); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("PollTimeout(0) error = %v, want DeadlineExceeded", err)
+	}
+
+	in.Stop()
+	if _, err := in.PollTimeout(time.Second); !errors.Is(err, ErrInputClosed) {
+		t.Errorf("PollTimeout() after Stop error = %v, want ErrInputClosed", err)
+	}
+}
+
+// TestKeyStateWithoutConsuming validates that key and modifier state track
+// captured events even when nobody dequeues them, including once the queue
+// is full.
+func TestKeyStateWithoutConsuming(t *testing.T) {
+	backend := newFakeBackend()
+	in := newTestInput(backend)
+	if err := in.Start(); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	defer in.Stop()
+
+	backend.events <- Event{Key: KeyW, Pressed: true}
+	backend.events <- Event{Key: KeyA, Modifiers: ModShift, Pressed: true}
+	waitQueued(t, in, 2)
+
+	if !in.IsPressed(KeyW) || !in.IsPressed(KeyA) {
+		t.Error("IsPressed() false for captured key-downs that were not consumed")
+	}
+	if got := in.Modifiers(); got != ModShift {
+		t.Errorf("Modifiers() = %v, want %v", got, ModShift)
+	}
+
+	backend.events <- Event{Key: KeyW, Pressed: false}
+	waitQueued(t, in, 3)
+	if in.IsPressed(KeyW) {
+		t.Error("IsPressed(KeyW) = true after captured key-up")
+	}
+	if got := in.Modifiers(); got != ModNone {
+		t.Errorf("Modifiers() = %v, want %v", got, ModNone)
+	}
+
+	// Key state keeps up after the queue fills, and the events that did
+	// not fit are delivered later, in order
+	for i := 0; i < in.bufferSize+50; i++ {
+		backend.events <- Event{Key: KeyW, Pressed: true}
+	}
+	backend.events <- Event{Key: KeyW, Pressed: false}
+	deadline := time.Now().Add(time.Second)
+	for in.IsPressed(KeyW) {
+		if time.Now().After(deadline) {
+			t.Fatal("IsPressed(KeyW) = true after key-up captured behind a full queue")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	want := 3 + in.bufferSize + 51
+	for n := 0; n < want; n++ {
+		event, err := in.PollTimeout(time.Second)
+		if err != nil {
+			t.Fatalf("PollTimeout() after %d events error = %v, want %d events", n, err, want)
+		}
+		if n == want-1 && event.Pressed {
+			t.Error("last event is a key-down, want the key-up")
+		}
+	}
+	if stats := in.OverflowStats(); stats != (OverflowStats{}) {
+		t.Errorf("OverflowStats() = %+v, want zero", stats)
+	}
+}
+
+// TestEvents validates that the Events channel delivers queued events in
+// order, works in select, and is closed by Stop.
+func TestEvents(t *testing.T) {
+	backend := newFakeBackend()
+	in := newTestInput(backend)
+	if err := in.Start(); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+
+	backend.events <- Event{Key: KeyA, Pressed: true}
+	backend.events <- Event{Key: KeyB, Pressed: true}
+	for _, want := range []Key{KeyA, KeyB} {
+		select {
+		case event := <-in.Events():
+			if event.Key != want {
+				t.Errorf("event = %v, want %v", event.Key, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("no event on Events() channel, want %v", want)
+		}
+	}
+
+	events := in.Events()
+	in.Stop()
+	if _, ok := <-events; ok {
+		t.Error("Events() channel not closed by Stop")
+	}
+}
+
+// TestAll validates that ranging over All yields events in order and ends
+// when the system stops.
+func TestAll(t *testing.T) {
+	backend := newFakeBackend()
+	in := newTestInput(backend)
+	if err := in.Start(); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+
+	backend.events <- Event{Key: KeyA, Pressed: true}
+	backend.events <- Event{Key: KeyB, Pressed: true}
+
+	var got []Key
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for event := range in.All() {
+			got = append(got, event.Key)
+			if len(got) == 2 {
+				in.Stop()
+			}
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("range over All() did not end after Stop")
+	}
+	if len(got) != 2 || got[0] != KeyA || got[1] != KeyB {
+		t.Errorf("All() yielded %v, want [A B]", got)
+	}
+}
+
+// TestDrainInto validates batched draining: events are copied oldest
+// first, at most len(buf) at a time, with the rest left queued.
+func TestDrainInto(t *testing.T) {
+	backend := newFakeBackend()
+	in := newTestInput(backend)
+	if err := in.Start(); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	defer in.Stop()
+
+	for _, k := range []Key{KeyA, KeyB, KeyC} {
+		backend.events <- Event{Key: k, Pressed: true}
+	}
+	waitQueued(t, in, 3)
+
+	tests := []struct {
+		name string
+		size int
+		want []Key
+	}{
+		{"partial", 2, []Key{KeyA, KeyB}},
+		{"rest", 8, []Key{KeyC}},
+		{"empty", 8, nil},
+		{"zero-length buffer", 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := make([]Event, tt.size)
+			n := in.DrainInto(buf)
+			if n != len(tt.want) {
+				t.Fatalf("DrainInto() = %d, want %d", n, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if buf[i].Key != want {
+					t.Errorf("buf[%d].Key = %v, want %v", i, buf[i].Key, want)
+				}
+			}
+		})
+	}
+}
+
+// TestNextIntoAllocations validates that NextInto and DrainInto do not
+// allocate.
+func TestNextIntoAllocations(t *testing.T) {
+	backend := newFakeBackend()
+	in := newTestInput(backend)
+	if err := in.Start(); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	defer in.Stop()
+
+	var (
+		event Event
+		buf   [16]Event
+	)
+	if got := testing.AllocsPerRun(100, func() { in.NextInto(&event) }); got != 0 {
+		t.Errorf("NextInto allocations = %v, want 0", got)
+	}
+	if got := testing.AllocsPerRun(100, func() { in.DrainInto(buf[:]) }); got != 0 {
+		t.Errorf("DrainInto allocations = %v, want 0", got)
+	}
+
+	backend.events <- Event{Key: KeyA, Pressed: true}
+	waitQueued(t, in, 1)
+	if !in.NextInto(&event) || event.Key != KeyA {
+		t.Errorf("NextInto() = %v, want KeyA", event.Key)
+	}
+}
@@ -0,0 +1,63 @@
+package input
+
+// Protocol is a terminal input mode that changes what the terminal sends
+// for key presses. Protocols are enabled with WithProtocols.
+type Protocol uint8
+
+const (
+	// ProtocolApplicationCursor (DECCKM) makes the terminal send cursor keys
+	// as SS3 sequences (ESC O A) instead of CSI sequences (ESC [ A).
+	ProtocolApplicationCursor Protocol = iota + 1
+
+	// ProtocolApplicationKeypad (DECKPAM) makes the terminal send keypad
+	// keys as SS3 sequences, so they can be told apart from the main
+	// keyboard.
+	ProtocolApplicationKeypad
+
+	// ProtocolWin32Input enables Windows Terminal's win32 input mode
+	// (DECSET 9001), which reports key-down and key-up events with full
+	// modifier state. Terminals that do not support it ignore the request.
+	ProtocolWin32Input
+)
+
+// String returns the name of the protocol.
+func (p Protocol) String() string {
+	switch p {
+	case ProtocolApplicationCursor:
+		return "ApplicationCursor"
+	case ProtocolApplicationKeypad:
+		return "ApplicationKeypad"
+	case ProtocolWin32Input:
+		return "Win32Input"
+	default:
+		return "Unknown"
+	}
+}
+
+// enableSequence returns the control sequence that turns the protocol on.
+func (p Protocol) enableSequence() string {
+	switch p {
+	case ProtocolApplicationCursor:
+		return "\x1b[?1h"
+	case ProtocolApplicationKeypad:
+		return "\x1b="
+	case ProtocolWin32Input:
+		return "\x1b[?9001h"
+	default:
+		return ""
+	}
+}
+
+// disableSequence returns the control sequence that turns the protocol off.
+func (p Protocol) disableSequence() string {
+	switch p {
+	case ProtocolApplicationCursor:
+		return "\x1b[?1l"
+	case ProtocolApplicationKeypad:
+		return "\x1b>"
+	case ProtocolWin32Input:
+		return "\x1b[?9001l"
+	default:
+		return ""
+	}
+}
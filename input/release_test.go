@@ -0,0 +1,132 @@
+package input
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestRelease validates that Release gives the terminal back while fn runs
+// and resumes capture afterwards, keeping queued events and forgetting
+// held keys.
+func TestRelease(t *testing.T) {
+	backend := newFakeBackend()
+	in := newTestInput(backend)
+	if err := in.Start(); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	defer in.Stop()
+
+	backend.events <- Event{Key: KeyW, Pressed: true}
+	if _, err := in.PollTimeout(time.Second); err != nil {
+		t.Fatalf("PollTimeout() error: %v", err)
+	}
+	backend.events <- Event{Key: KeyA, Pressed: true}
+	waitQueued(t, in, 1)
+
+	errEditor := errors.New("editor failed")
+	err := in.Release(func() error {
+		backend.mu.Lock()
+		defer backend.mu.Unlock()
+		if backend.restores != 1 {
+			t.Errorf("terminal not restored while fn runs: %d restores", backend.restores)
+		}
+		return errEditor
+	})
+	if !errors.Is(err, errEditor) {
+		t.Errorf("Release() error = %v, want %v", err, errEditor)
+	}
+
+	if backend.inits != 2 || backend.restores != 1 {
+		t.Errorf("backend Init/Restore calls = %d/%d, want 2/1", backend.inits, backend.restores)
+	}
+	if in.IsPressed(KeyW) {
+		t.Error("key state not cleared by Release")
+	}
+
+	// The event queued before Release is still there, followed by new input
+	backend.events <- Event{Key: KeyB, Pressed: true}
+	for _, want := range []Key{KeyA, KeyB} {
+		event, err := in.PollTimeout(time.Second)
+		if err != nil || event.Key != want {
+			t.Fatalf("PollTimeout() = (%v, %v), want %v", event.Key, err, want)
+		}
+	}
+}
+
+// TestReleaseKeepsPollBlocked validates that a Poll blocked during Release
+// is not woken as if the input had stopped.
+func TestReleaseKeepsPollBlocked(t *testing.T) {
+	backend := newFakeBackend()
+	in := newTestInput(backend)
+	if err := in.Start(); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	defer in.Stop()
+
+	result := make(chan error, 1)
+	go func() {
+		_, err := in.PollTimeout(5 * time.Second)
+		result <- err
+	}()
+
+	if err := in.Release(func() error { return nil }); err != nil {
+		t.Fatalf("Release() error: %v", err)
+	}
+	backend.events <- Event{Key: KeyA, Pressed: true}
+
+	if err := <-result; err != nil {
+		t.Errorf("blocked PollTimeout() error = %v, want an event", err)
+	}
+}
+
+// TestReleaseNotStarted validates that Release just calls fn when the
+// input is not started.
+func TestReleaseNotStarted(t *testing.T) {
+	backend := newFakeBackend()
+	in := newTestInput(backend)
+
+	called := false
+	if err := in.Release(func() error { called = true; return nil }); err != nil {
+		t.Fatalf("Release() error: %v", err)
+	}
+	if !called {
+		t.Error("fn not called")
+	}
+	if backend.inits != 0 || backend.restores != 0 {
+		t.Errorf("backend Init/Restore calls = %d/%d, want 0/0", backend.inits, backend.restores)
+	}
+}
+
+// TestReleaseInitFailure validates that the input stops when the terminal
+// cannot be taken back after fn.
+func TestReleaseInitFailure(t *testing.T) {
+	backend := newFakeBackend()
+	in := newTestInput(backend)
+	if err := in.Start(); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+
+	errTTY := errors.New("tty gone")
+	err := in.Release(func() error {
+		backend.mu.Lock()
+		backend.initErr = errTTY
+		backend.mu.Unlock()
+		return nil
+	})
+	if !errors.Is(err, errTTY) {
+		t.Errorf("Release() error = %v, want %v", err, errTTY)
+	}
+
+	if _, err := in.PollTimeout(time.Second); !errors.Is(err, ErrInputClosed) {
+		t.Errorf("PollTimeout() error = %v, want ErrInputClosed", err)
+	}
+	in.Stop()
+
+	// The input can be started again once the terminal is back
+	backend.initErr = nil
+	if err := in.Start(); err != nil {
+		t.Fatalf("Start() after failed Release error: %v", err)
+	}
+	in.Stop()
+}
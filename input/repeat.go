@@ -0,0 +1,136 @@
+package input
+
+import "time"
+
+// Bounds used to classify and learn autorepeat timing.
+const (
+	// fastRepeatGap is the longest gap between two presses of the same key
+	// that nobody can type by hand; a faster press, unless part of a
+	// burst, is always a repeat.
+	fastRepeatGap = 80 * time.Millisecond
+
+	// minRepeatGap is the shortest gap between autorepeats; presses closer
+	// together arrived in one burst, such as pasted text.
+	minRepeatGap = 10 * time.Millisecond
+
+	// minRepeatDelay and maxRepeatDelay bound the repeat delays that can
+	// be learned.
+	minRepeatDelay = 150 * time.Millisecond
+	maxRepeatDelay = 2 * time.Second
+)
+
+// repeatDetector sets Event.Repeat on terminals that report autorepeats as
+// ordinary presses. Holding a key sends a press, then after the keyboard's
+// repeat delay a press every repeat interval. A press of the same key as
+// the previous event is a repeat if it follows an initial press after
+// about the delay, or a repeat after about the interval.
+//
+// Until the delay and interval are configured (see WithRepeatTiming) they
+// are learned from runs of presses too fast to be typed by hand; before
+// then only such fast presses are classified as repeats.
+//
+// A repeatDetector is owned by a single capture goroutine.
+type repeatDetector struct {
+	delay    time.Duration
+	interval time.Duration
+
+	// learnDelay and learnInterval are set for timings not configured.
+	learnDelay    bool
+	learnInterval bool
+
+	// last is the previous key press, captured at lastAt; run counts the
+	// presses of the same key that followed it, and firstGap is the gap
+	// before the first of them.
+	last     Event
+	lastAt   time.Time
+	inRun    bool
+	run      int
+	firstGap time.Duration
+}
+
+// newRepeatDetector returns a detector using the given timings. Timings
+// <= 0 are learned.
+func newRepeatDetector(delay, interval time.Duration) *repeatDetector {
+	return &repeatDetector{
+		delay:         max(delay, 0),
+		interval:      max(interval, 0),
+		learnDelay:    delay <= 0,
+		learnInterval: interval <= 0,
+	}
+}
+
+// observe classifies an event captured at now and returns it, with Repeat
+// set if it is an autorepeat. Events the terminal already marked as
+// repeats are kept as they are.
+func (d *repeatDetector) observe(event Event, now time.Time) Event {
+	if event.Type != EventKey || !event.Pressed {
+		// A key-up or resume ends any run of repeats
+		d.endRun()
+		return event
+	}
+
+	gap := now.Sub(d.lastAt)
+	same := d.inRun && sameKeyEvent(event, d.last)
+	d.last, d.lastAt, d.inRun = event, now, true
+
+	if !same {
+		d.run = 0
+		return event
+	}
+
+	d.run++
+	if d.run == 1 {
+		d.firstGap = gap
+	}
+	if gap < minRepeatGap {
+		return event
+	}
+	if d.run == 1 {
+		if d.delay > 0 {
+			event.Repeat = event.Repeat || gap >= d.delay*3/4 && gap <= d.delay*3/2
+		} else {
+			event.Repeat = event.Repeat || gap <= fastRepeatGap
+		}
+		return event
+	}
+
+	d.learn(gap)
+	event.Repeat = event.Repeat || gap <= d.intervalLimit()
+	return event
+}
+
+// endRun forgets the previous press, so the next one is not a repeat.
+func (d *repeatDetector) endRun() {
+	d.inRun = false
+}
+
+// learn updates learned timings from the gap before a second or later
+// press in a run.
+func (d *repeatDetector) learn(gap time.Duration) {
+	if gap > fastRepeatGap {
+		return
+	}
+	if d.learnInterval {
+		if d.interval == 0 {
+			d.interval = gap
+		} else {
+			d.interval = (d.interval*3 + gap) / 4
+		}
+	}
+	if d.learnDelay && d.run == 2 && d.firstGap >= minRepeatDelay && d.firstGap <= maxRepeatDelay {
+		if d.delay == 0 {
+			d.delay = d.firstGap
+		} else {
+			d.delay = (d.delay*3 + d.firstGap) / 4
+		}
+	}
+}
+
+// intervalLimit returns the longest gap between two repeats.
+func (d *repeatDetector) intervalLimit() time.Duration {
+	if d.interval == 0 {
+		return fastRepeatGap
+	}
+	// Allow for scheduling jitter on short intervals
+	return max(d.interval*3/2, d.interval+10*time.Millisecond)
+}
@@ -0,0 +1,107 @@
+package input
+
+import (
+	"testing"
+	"time"
+)
+
+// TestRepeatDetector validates autorepeat classification from press timing,
+// with configured and learned repeat delay and interval.
+func TestRepeatDetector(t *testing.T) {
+	type press struct {
+		key Key
+		ms  int
+	}
+	tests := []struct {
+		name         string
+		delay        time.Duration
+		interval     time.Duration
+		presses      []press
+		want         []bool
+		wantDelay    time.Duration
+		wantInterval time.Duration
+	}{
+		{
+			name:     "configured hold",
+			delay:    500 * time.Millisecond,
+			interval: 30 * time.Millisecond,
+			presses:  []press{{KeyW, 0}, {KeyW, 500}, {KeyW, 530}, {KeyW, 560}},
+			want:     []bool{false, true, true, true},
+		},
+		{
+			name:     "configured double tap",
+			delay:    500 * time.Millisecond,
+			interval: 30 * time.Millisecond,
+			presses:  []press{{KeyL, 0}, {KeyL, 150}, {KeyL, 300}},
+			want:     []bool{false, false, false},
+		},
+		{
+			name:     "other key ends run",
+			delay:    500 * time.Millisecond,
+			interval: 30 * time.Millisecond,
+			presses:  []press{{KeyW, 0}, {KeyA, 100}, {KeyW, 600}},
+			want:     []bool{false, false, false},
+		},
+		{
+			name:    "burst is not a repeat",
+			presses: []press{{KeyO, 0}, {KeyO, 1}},
+			want:    []bool{false, false},
+		},
+		{
+			name:         "learned hold",
+			presses:      []press{{KeyW, 0}, {KeyW, 500}, {KeyW, 533}, {KeyW, 566}},
+			want:         []bool{false, false, true, true},
+			wantDelay:    500 * time.Millisecond,
+			wantInterval: 33 * time.Millisecond,
+		},
+		{
+			name: "learned timing applies to next hold",
+			presses: []press{
+				{KeyW, 0}, {KeyW, 500}, {KeyW, 540}, {KeyW, 580},
+				{KeyS, 1000}, {KeyS, 1500}, {KeyS, 1540},
+			},
+			want:         []bool{false, false, true, true, false, true, true},
+			wantDelay:    500 * time.Millisecond,
+			wantInterval: 40 * time.Millisecond,
+		},
+		{
+			name:    "unlearned double tap",
+			presses: []press{{KeyL, 0}, {KeyL, 150}},
+			want:    []bool{false, false},
+		},
+	}
+
+	t0 := time.Unix(0, 0)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newRepeatDetector(tt.delay, tt.interval)
+			for i, p := range tt.presses {
+				at := t0.Add(time.Duration(p.ms) * time.Millisecond)
+				got := d.observe(Event{Key: p.key, Pressed: true}, at)
+				if got.Repeat != tt.want[i] {
+					t.Errorf("press %d (%v at %dms): Repeat = %v, want %v", i, p.key, p.ms, got.Repeat, tt.want[i])
+				}
+			}
+			if tt.wantDelay != 0 && d.delay != tt.wantDelay {
+				t.Errorf("learned delay = %v, want %v", d.delay, tt.wantDelay)
+			}
+			if tt.wantInterval != 0 && d.interval != tt.wantInterval {
+				t.Errorf("learned interval = %v, want %v", d.interval, tt.wantInterval)
+			}
+		})
+	}
+}
+
+// TestRepeatDetectorKeyUp validates that a key-up ends a run, so the next
+// press of the same key is not a repeat.
+func TestRepeatDetectorKeyUp(t *testing.T) {
+	d := newRepeatDetector(500*time.Millisecond, 30*time.Millisecond)
+	t0 := time.Unix(0, 0)
+
+	d.observe(Event{Key: KeyW, Pressed: true}, t0)
+	d.observe(Event{Key: KeyW, Pressed: false}, t0.Add(100*time.Millisecond))
+	got := d.observe(Event{Key: KeyW, Pressed: true}, t0.Add(500*time.Millisecond))
+	if got.Repeat {
+		t.Error("press after key-up marked as repeat")
+	}
+}
@@ -0,0 +1,179 @@
+package input
+
+import (
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+// TestRestart validates Start→Stop→Start cycles: each session delivers
+// events on fresh channels with empty key state, and the backend is
+// initialized and restored once per session.
+func TestRestart(t *testing.T) {
+	backend := newFakeBackend()
+	in := newTestInput(backend)
+
+	for session := 1; session <= 3; session++ {
+		if err := in.Start(); err != nil {
+			t.Fatalf("session %d: Start() error: %v", session, err)
+		}
+		if in.IsPressed(KeyA) {
+			t.Errorf("session %d: key state not reset", session)
+		}
+
+		backend.events <- Event{Key: KeyA, Pressed: true}
+		event, err := in.PollTimeout(time.Second)
+		if err != nil || event.Key != KeyA {
+			t.Fatalf("session %d: PollTimeout() = (%v, %v), want KeyA", session, event.Key, err)
+		}
+		if !in.IsPressed(KeyA) {
+			t.Errorf("session %d: IsPressed(KeyA) = false after key-down", session)
+		}
+
+		in.Stop()
+
+		if _, ok := in.Poll(); ok {
+			t.Errorf("session %d: Poll() after Stop returned an event", session)
+		}
+		if e := in.Next(); e != nil {
+			t.Errorf("session %d: Next() after Stop = %+v, want nil", session, e)
+		}
+	}
+
+	if backend.inits != 3 || backend.restores != 3 {
+		t.Errorf("backend Init/Restore calls = %d/%d, want 3/3", backend.inits, backend.restores)
+	}
+}
+
+// TestStartWhileStarted validates that Start fails on a running Input.
+func TestStartWhileStarted(t *testing.T) {
+	backend := newFakeBackend()
+	in := newTestInput(backend)
+	if err := in.Start(); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	defer in.Stop()
+
+	if err := in.Start(); !errors.Is(err, ErrAlreadyStarted) {
+		t.Errorf("second Start() error = %v, want ErrAlreadyStarted", err)
+	}
+}
+
+// TestResumeClearsKeyState validates that an EventResume event forgets keys
+// held before the process was suspended.
+func TestResumeClearsKeyState(t *testing.T) {
+	backend := newFakeBackend()
+	in := newTestInput(backend)
+	if err := in.Start(); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	defer in.Stop()
+
+	backend.events <- Event{Key: KeyW, Pressed: true}
+	waitQueued(t, in, 1)
+	if !in.IsPressed(KeyW) {
+		t.Fatal("IsPressed(KeyW) = false after key-down")
+	}
+
+	backend.events <- Event{Type: EventResume}
+	waitQueued(t, in, 2)
+	if in.IsPressed(KeyW) {
+		t.Error("IsPressed(KeyW) = true after resume, want false")
+	}
+}
+
+// TestCaptureEnded validates that when capture ends on its own, Done is
+// closed, Err reports why, queued events can still be received and Stop
+// keeps the reason.
+func TestCaptureEnded(t *testing.T) {
+	errRead := errors.New("read failed")
+	tests := []struct {
+		name  string
+		end   func(*fakeBackend)
+		wants []error
+	}{
+		{"terminal closed", func(b *fakeBackend) { b.close() }, []error{ErrInputClosed, io.EOF}},
+		{"too many errors", func(b *fakeBackend) {
+			b.mu.Lock()
+			b.readErr = errRead
+			b.mu.Unlock()
+			// Wake the blocked read; the following ones fail
+			b.events <- Event{Key: KeyB, Pressed: true}
+		}, []error{ErrTooManyErrors, errRead}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := newFakeBackend()
+			in := newTestInput(backend)
+			if err := in.Start(); err != nil {
+				t.Fatalf("Start() error: %v", err)
+			}
+			defer in.Stop()
+
+			if err := in.Err(); err != nil {
+				t.Errorf("Err() while running = %v, want nil", err)
+			}
+			backend.events <- Event{Key: KeyA, Pressed: true}
+			waitQueued(t, in, 1)
+			tt.end(backend)
+
+			select {
+			case <-in.Done():
+			case <-time.After(3 * time.Second):
+				t.Fatal("Done() not closed after capture ended")
+			}
+			for _, want := range tt.wants {
+				if err := in.Err(); !errors.Is(err, want) {
+					t.Errorf("Err() = %v, want it to match %v", err, want)
+				}
+			}
+
+			if event, ok := in.Poll(); !ok || event.Key != KeyA {
+				t.Errorf("Poll() = (%v, %v), want queued KeyA", event.Key, ok)
+			}
+			for range in.All() {
+				// Drain the rest; the loop must end
+			}
+
+			in.Stop()
+			if err := in.Err(); !errors.Is(err, tt.wants[0]) {
+				t.Errorf("Err() after Stop = %v, want the original reason", err)
+			}
+		})
+	}
+}
+
+// TestErrAfterStop validates Err and Done across a Stop and restart.
+func TestErrAfterStop(t *testing.T) {
+	backend := newFakeBackend()
+	in := newTestInput(backend)
+	if err := in.Start(); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	done := in.Done()
+	in.Stop()
+
+	select {
+	case <-done:
+	default:
+		t.Error("Done() not closed by Stop")
+	}
+	if err := in.Err(); err != ErrInputClosed {
+		t.Errorf("Err() after Stop = %v, want ErrInputClosed", err)
+	}
+
+	if err := in.Start(); err != nil {
+		t.Fatalf("restart error: %v", err)
+	}
+	defer in.Stop()
+	if err := in.Err(); err != nil {
+		t.Errorf("Err() after restart = %v, want nil", err)
+	}
+	select {
+	case <-in.Done():
+		t.Error("Done() of new session already closed")
+	default:
+	}
+}
@@ -0,0 +1,118 @@
+package input
+
+import (
+	"slices"
+	"time"
+)
+
+// Snapshot is the input state frozen at a frame boundary by Input.Update.
+// Level queries (IsPressed, HeldDuration) describe the keys held at Time;
+// edge queries (JustPressed, JustReleased) and Events describe what happened
+// since the previous Update.
+//
+// A Snapshot is immutable and safe to share between goroutines. The zero
+// Snapshot has no keys held and no events.
+type Snapshot struct {
+	// Time is when the snapshot was taken.
+	Time time.Time
+
+	events   []Event
+	held     []keyHold
+	pressed  []Key
+	released []Key
+}
+
+// keyHold is a key held down since a given time.
+type keyHold struct {
+	key   Key
+	since time.Time
+}
+
+// IsPressed reports whether k was held down at the end of the frame.
+func (s Snapshot) IsPressed(k Key) bool {
+	return s.holdIndex(k) >= 0
+}
+
+// JustPressed reports whether k went down during the frame. Autorepeats
+// of a held key do not count; other presses do, even without a key-up in
+// between. A key pressed and released within the same
+// frame is both JustPressed and JustReleased.
+func (s Snapshot) JustPressed(k Key) bool {
+	return slices.Contains(s.pressed, k)
+}
+
+// JustReleased reports whether k went up during the frame.
+func (s Snapshot) JustReleased(k Key) bool {
+	return slices.Contains(s.released, k)
+}
+
+// HeldDuration returns how long k had been held at the end of the frame,
+// or 0 if it was not held.
+func (s Snapshot) HeldDuration(k Key) time.Duration {
+	i := s.holdIndex(k)
+	if i < 0 {
+		return 0
+	}
+	return s.Time.Sub(s.held[i].since)
+}
+
+// Events returns the events that occurred during the frame, oldest first.
+// The returned slice is shared and must not be modified.
+func (s Snapshot) Events() []Event {
+	return s.events
+}
+
+// holdIndex returns the index of k in s.held, or -1.
+func (s Snapshot) holdIndex(k Key) int {
+	return slices.IndexFunc(s.held, func(h keyHold) bool { return h.key == k })
+}
+
+// nextSnapshot starts the snapshot following prev, taken at now. Keys held
+// in prev stay held unless reset is set, as after a restart or Release
+// when key-ups may have been missed.
+func nextSnapshot(prev Snapshot, now time.Time, reset bool) Snapshot {
+	next := Snapshot{Time: now}
+	if !reset {
+		next.held = slices.Clone(prev.held)
+	}
+	return next
+}
+
+// apply records event as having occurred during the frame.
+func (s *Snapshot) apply(event Event) {
+	s.events = append(s.events, event)
+
+	switch {
+	case event.Type == EventResume:
+		// Key-ups were missed while suspended
+		s.held = s.held[:0]
+
+	case event.Pressed:
+		since := event.Timestamp
+		if since.IsZero() {
+			since = s.Time
+		}
+		if i := s.holdIndex(event.Key); i >= 0 {
+			if event.Repeat {
+				// Autorepeat of a held key
+				return
+			}
+			// A new press without a reported release, as on terminals
+			// that only report presses: the key is held anew
+			s.held[i].since = since
+		} else {
+			s.held = append(s.held, keyHold{key: event.Key, since: since})
+		}
+		if !s.JustPressed(event.Key) {
+			s.pressed = append(s.pressed, event.Key)
+		}
+
+	default:
+		if i := s.holdIndex(event.Key); i >= 0 {
+			s.held = slices.Delete(s.held, i, i+1)
+		}
+		if !s.JustReleased(event.Key) {
+			s.released = append(s.released, event.Key)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package input
+
+import (
+	"testing"
+	"time"
+)
+
+// TestSnapshotEdges validates edge and level queries of a frame built from
+// a sequence of events.
+func TestSnapshotEdges(t *testing.T) {
+	t0 := time.Unix(100, 0)
+	down := func(k Key, ms int) Event {
+		return Event{Key: k, Pressed: true, Timestamp: t0.Add(time.Duration(ms) * time.Millisecond)}
+	}
+	up := func(k Key) Event { return Event{Key: k} }
+	repeat := func(k Key) Event { return Event{Key: k, Pressed: true, Repeat: true} }
+
+	tests := []struct {
+		name         string
+		prevHeld     []Key
+		reset        bool
+		events       []Event
+		key          Key
+		wantPressed  bool
+		wantJustDown bool
+		wantJustUp   bool
+		wantHeld     time.Duration
+	}{
+		{"press", nil, false, []Event{down(KeyW, 0)}, KeyW, true, true, false, time.Second},
+		{"held from previous frame", []Key{KeyW}, false, nil, KeyW, true, false, false, 2 * time.Second},
+		{"repeat is not an edge", []Key{KeyW}, false, []Event{repeat(KeyW)}, KeyW, true, false, false, 2 * time.Second},
+		{"press without release", []Key{KeyW}, false, []Event{down(KeyW, 0)}, KeyW, true, true, false, time.Second},
+		{"release", []Key{KeyW}, false, []Event{up(KeyW)}, KeyW, false, false, true, 0},
+		{"tap within frame", nil, false, []Event{down(KeyW, 0), up(KeyW)}, KeyW, false, true, true, 0},
+		{"resume forgets held keys", []Key{KeyW}, false, []Event{{Type: EventResume}}, KeyW, false, false, false, 0},
+		{"reset forgets held keys", []Key{KeyW}, true, nil, KeyW, false, false, false, 0},
+		{"other key", nil, false, []Event{down(KeyA, 0)}, KeyW, false, false, false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var prev Snapshot
+			for _, k := range tt.prevHeld {
+				prev.held = append(prev.held, keyHold{key: k, since: t0.Add(-time.Second)})
+			}
+
+			s := nextSnapshot(prev, t0.Add(time.Second), tt.reset)
+			for _, e := range tt.events {
+				s.apply(e)
+			}
+
+			if got := s.IsPressed(tt.key); got != tt.wantPressed {
+				t.Errorf("IsPressed = %v, want %v", got, tt.wantPressed)
+			}
+			if got := s.JustPressed(tt.key); got != tt.wantJustDown {
+				t.Errorf("JustPressed = %v, want %v", got, tt.wantJustDown)
+			}
+			if got := s.JustReleased(tt.key); got != tt.wantJustUp {
+				t.Errorf("JustReleased = %v, want %v", got, tt.wantJustUp)
+			}
+			if got := s.HeldDuration(tt.key); got != tt.wantHeld {
+				t.Errorf("HeldDuration = %v, want %v", got, tt.wantHeld)
+			}
+			if got := len(s.Events()); got != len(tt.events) {
+				t.Errorf("len(Events()) = %d, want %d", got, len(tt.events))
+			}
+			if len(prev.held) > 0 && prev.held[0].key != tt.prevHeld[0] {
+				t.Error("next snapshot modified the previous one")
+			}
+		})
+	}
+}
+
+// TestUpdate validates that Update drains queued events into a frame,
+// that the next frame only reports new edges, and that Snapshot returns
+// the last frame without consuming events.
+func TestUpdate(t *testing.T) {
+	backend := newFakeBackend()
+	in := newTestInput(backend)
+	if err := in.Start(); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	defer in.Stop()
+
+	backend.events <- Event{Key: KeySpace, Pressed: true}
+	waitQueued(t, in, 1)
+
+	frame := in.Update()
+	if !frame.JustPressed(KeySpace) || !frame.IsPressed(KeySpace) {
+		t.Errorf("first frame: JustPressed/IsPressed(Space) = %v/%v, want true/true",
+			frame.JustPressed(KeySpace), frame.IsPressed(KeySpace))
+	}
+	if len(frame.Events()) != 1 {
+		t.Errorf("first frame has %d events, want 1", len(frame.Events()))
+	}
+
+	backend.events <- Event{Key: KeyA, Pressed: true}
+	waitQueued(t, in, 1)
+	if got := in.Snapshot(); !got.JustPressed(KeySpace) {
+		t.Error("Snapshot() does not return the last frame")
+	}
+
+	frame = in.Update()
+	if frame.JustPressed(KeySpace) || !frame.IsPressed(KeySpace) {
+		t.Error("second frame: Space should be held without a new edge")
+	}
+	if !frame.JustPressed(KeyA) {
+		t.Error("second frame: JustPressed(KeyA) = false")
+	}
+	if e := in.Next(); e != nil {
+		t.Errorf("Next() after Update = %+v, want nil", e)
+	}
+}
+
+// TestUpdatePressesWithoutRelease validates that on terminals without
+// key-ups every new press of a key is an edge in its frame.
+func TestUpdatePressesWithoutRelease(t *testing.T) {
+	backend := newFakeBackend()
+	in := newTestInput(backend)
+	if err := in.Start(); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	defer in.Stop()
+
+	for i := 0; i < 3; i++ {
+		if i > 0 {
+			// Slow enough not to be taken for an autorepeat
+			time.Sleep(2 * fastRepeatGap)
+		}
+		backend.events <- Event{Key: KeySpace, Pressed: true}
+		waitQueued(t, in, 1)
+
+		if frame := in.Update(); !frame.JustPressed(KeySpace) {
+			t.Errorf("frame %d: JustPressed(Space) = false for a new press", i)
+		}
+	}
+	if frame := in.Update(); frame.JustPressed(KeySpace) {
+		t.Error("frame without a press: JustPressed(Space) = true")
+	}
+}
+
+// TestGameSnapshot validates action-level queries over a frame.
+func TestGameSnapshot(t *testing.T) {
+	backend := newFakeBackend()
+	in := newTestInput(backend)
+	game := NewGameInput(in)
+	if err := game.Start(); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	defer in.Stop()
+
+	game.Bind("move-up", KeyW, KeyUp)
+	backend.events <- Event{Key: KeyW, Pressed: true}
+	backend.events <- Event{Key: KeyUp, Pressed: true}
+	waitQueued(t, in, 2)
+
+	frame := game.Update()
+	if !frame.ActionJustPressed("move-up") || !frame.IsActionPressed("move-up") {
+		t.Error("move-up should be just pressed and held")
+	}
+	if frame.ActionJustPressed("jump") || frame.IsActionPressed("jump") {
+		t.Error("unbound action reported pressed")
+	}
+
+	// Rebinding does not change a snapshot already taken
+	game.Bind("move-up", KeyK)
+	if !frame.IsActionPressed("move-up") {
+		t.Error("Bind changed an existing snapshot")
+	}
+	game.Bind("move-up", KeyW, KeyUp)
+
+	// Releasing one of two held keys does not end the action
+	backend.events <- Event{Key: KeyW}
+	waitQueued(t, in, 1)
+	if frame = game.Update(); frame.ActionJustReleased("move-up") {
+		t.Error("ActionJustReleased with a bound key still held")
+	}
+
+	backend.events <- Event{Key: KeyUp}
+	waitQueued(t, in, 1)
+	frame = game.Update()
+	if !frame.ActionJustReleased("move-up") || frame.IsActionPressed("move-up") {
+		t.Error("move-up should be just released")
+	}
+	if d := frame.ActionHeldDuration("move-up"); d != 0 {
+		t.Errorf("ActionHeldDuration after release = %v, want 0", d)
+	}
+}
@@ -0,0 +1,143 @@
+package input
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// defaultSubscriptionBuffer is the capacity of a subscription's channel
+// when WithSubscriptionBuffer is not given.
+const defaultSubscriptionBuffer = 64
+
+// SubscribeOption configures a Subscription created by Input.Subscribe.
+type SubscribeOption func(*subscribeConfig)
+
+// subscribeConfig holds the settings collected from SubscribeOptions.
+type subscribeConfig struct {
+	// bufferSize is the capacity of the subscription channel.
+	bufferSize int
+
+	// filter selects the events delivered; nil means all.
+	filter func(Event) bool
+}
+
+// WithSubscriptionBuffer sets the capacity of the subscription's channel
+// (default 64). Events arriving while it is full are dropped for this
+// subscription only and counted by Subscription.Dropped. Values < 0 are
+// ignored.
+func WithSubscriptionBuffer(n int) SubscribeOption {
+	return func(c *subscribeConfig) {
+		if n >= 0 {
+			c.bufferSize = n
+		}
+	}
+}
+
+// WithSubscriptionFilter delivers only the events for which filter returns
+// true. The filter runs on the capture goroutine, so it must be fast and
+// must not block or call Subscription.Close. A panic in filter is re-raised
+// after the terminal is restored, and ends the program.
+func WithSubscriptionFilter(filter func(Event) bool) SubscribeOption {
+	return func(c *subscribeConfig) {
+		c.filter = filter
+	}
+}
+
+// Subscription is an independent view of the event stream created by
+// Input.Subscribe. Every subscription receives every captured event that
+// passes its filter, regardless of other subscriptions and of Poll/Next
+// consumers.
+type Subscription struct {
+	events  chan Event
+	filter  func(Event) bool
+	dropped atomic.Uint64
+
+	// hub is the registry the subscription belongs to; closed is set
+	// once events has been closed. Both are guarded by hub.mu.
+	hub    *subscribers
+	closed bool
+}
+
+// Events returns the channel events are delivered on. It is closed when
+// the subscription is closed or the input system stops.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Close stops delivery and closes the Events channel. Events still in the
+// channel can be received after Close. Safe to call multiple times.
+func (s *Subscription) Close() {
+	s.hub.remove(s)
+}
+
+// Dropped returns how many events were dropped because the subscription's
+// channel was full.
+func (s *Subscription) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// subscribers is the set of open subscriptions of an Input.
+type subscribers struct {
+	mu   sync.Mutex
+	subs []*Subscription
+}
+
+// add creates and registers a subscription.
+func (h *subscribers) add(opts []SubscribeOption) *Subscription {
+	cfg := subscribeConfig{bufferSize: defaultSubscriptionBuffer}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+
+	s := &Subscription{
+		events: make(chan Event, cfg.bufferSize),
+		filter: cfg.filter,
+		hub:    h,
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.subs = append(h.subs, s)
+	return s
+}
+
+// remove unregisters s and closes its channel.
+func (h *subscribers) remove(s *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if s.closed {
+		return
+	}
+	h.subs = slices.DeleteFunc(h.subs, func(other *Subscription) bool { return other == s })
+	s.closed = true
+	close(s.events)
+}
+
+// closeAll closes every open subscription.
+func (h *subscribers) closeAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, s := range h.subs {
+		s.closed = true
+		close(s.events)
+	}
+	h.subs = nil
+}
+
+// broadcast offers event to every subscription without blocking.
+func (h *subscribers) broadcast(event Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, s := range h.subs {
+		if s.filter != nil && !s.filter(event) {
+			continue
+		}
+		select {
+		case s.events <- event:
+		default:
+			s.dropped.Add(1)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package input
+
+import (
+	"testing"
+	"time"
+)
+
+// TestSubscribe validates that every subscription and the main queue each
+// receive every event, subject to subscription filters.
+func TestSubscribe(t *testing.T) {
+	backend := newFakeBackend()
+	in := newTestInput(backend)
+	all := in.Subscribe()
+	letters := in.Subscribe(WithSubscriptionFilter(func(e Event) bool { return e.Rune != 0 }))
+	if err := in.Start(); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	defer in.Stop()
+
+	backend.events <- Event{Key: KeyA, Rune: 'a', Pressed: true}
+	backend.events <- Event{Key: KeyUp, Pressed: true}
+
+	for _, want := range []Key{KeyA, KeyUp} {
+		if event, err := in.PollTimeout(time.Second); err != nil || event.Key != want {
+			t.Fatalf("PollTimeout() = (%v, %v), want %v", event.Key, err, want)
+		}
+	}
+
+	tests := []struct {
+		name string
+		sub  *Subscription
+		want []Key
+	}{
+		{"unfiltered", all, []Key{KeyA, KeyUp}},
+		{"filtered", letters, []Key{KeyA}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, want := range tt.want {
+				select {
+				case event := <-tt.sub.Events():
+					if event.Key != want {
+						t.Errorf("subscription event = %v, want %v", event.Key, want)
+					}
+				case <-time.After(time.Second):
+					t.Fatalf("subscription did not receive %v", want)
+				}
+			}
+			select {
+			case event := <-tt.sub.Events():
+				t.Errorf("unexpected subscription event %v", event.Key)
+			default:
+			}
+		})
+	}
+}
+
+// TestSubscriptionOverflow validates that a full subscription drops events
+// without holding up the main queue.
+func TestSubscriptionOverflow(t *testing.T) {
+	backend := newFakeBackend()
+	in := newTestInput(backend)
+	sub := in.Subscribe(WithSubscriptionBuffer(1))
+	if err := in.Start(); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	defer in.Stop()
+
+	for _, k := range []Key{KeyA, KeyB, KeyC} {
+		backend.events <- Event{Key: k, Pressed: true}
+	}
+	waitQueued(t, in, 3)
+
+	if got := sub.Dropped(); got != 2 {
+		t.Errorf("Dropped() = %d, want 2", got)
+	}
+	if event := <-sub.Events(); event.Key != KeyA {
+		t.Errorf("kept event = %v, want KeyA", event.Key)
+	}
+}
+
+// TestSubscriptionClose validates that Close and Stop close subscription
+// channels, and that closing twice is harmless.
+func TestSubscriptionClose(t *testing.T) {
+	backend := newFakeBackend()
+	in := newTestInput(backend)
+	closed := in.Subscribe()
+	open := in.Subscribe()
+	if err := in.Start(); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+
+	closed.Close()
+	closed.Close()
+	if _, ok := <-closed.Events(); ok {
+		t.Error("Events() not closed by Close")
+	}
+
+	backend.events <- Event{Key: KeyA, Pressed: true}
+	if event := <-open.Events(); event.Key != KeyA {
+		t.Errorf("open subscription event = %v, want KeyA", event.Key)
+	}
+
+	in.Stop()
+	if _, ok := <-open.Events(); ok {
+		t.Error("Events() not closed by Stop")
+	}
+	open.Close()
+}
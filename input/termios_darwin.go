@@ -0,0 +1,12 @@
+//go:build darwin
+// +build darwin
+
+package input
+
+import "golang.org/x/sys/unix"
+
+// Requests for reading and writing the terminal attributes.
+const (
+	ioctlGetTermios = unix.TIOCGETA
+	ioctlSetTermios = unix.TIOCSETA
+)
@@ -0,0 +1,12 @@
+//go:build linux
+// +build linux
+
+package input
+
+import "golang.org/x/sys/unix"
+
+// Requests for reading and writing the terminal attributes.
+const (
+	ioctlGetTermios = unix.TCGETS
+	ioctlSetTermios = unix.TCSETS
+)
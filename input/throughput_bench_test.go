@@ -52,3 +52,20 @@ func BenchmarkParseUTF8_4byte(b *testing.B) {
 		_, _ = parser.Parse(seq)
 	}
 }
+
+// BenchmarkDrainInto measures draining a frame's worth of queued events
+// into a reused buffer.
+func BenchmarkDrainInto(b *testing.B) {
+	in := New(WithBufferSize(64)).(*inputImpl)
+	var buf [64]Event
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < 16; j++ {
+			in.events <- Event{Key: KeyA, Pressed: true}
+		}
+		in.DrainInto(buf[:])
+	}
+}
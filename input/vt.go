@@ -0,0 +1,373 @@
+package input
+
+import (
+	"errors"
+	"unicode/utf8"
+)
+
+// vtState is a state of the input state machine that finds sequence
+// boundaries. It follows the ECMA-48 / DEC ANSI parser model (as described
+// by Paul Williams' VT500 parser) adapted to terminal input: it knows where
+// every well-formed sequence ends, whether or not the key it encodes is
+// recognized, so unknown sequences never desynchronize the stream.
+type vtState uint8
+
+const (
+	vtGround vtState = iota
+	vtEscape
+	vtEscapeIntermediate
+	vtCSIEntry
+	vtCSIParam
+	vtCSIIntermediate
+	vtCSIIgnore
+	vtSS3
+	vtLinuxFunction
+	vtOSCString
+	vtDCSEntry
+	vtDCSParam
+	vtDCSIntermediate
+	vtDCSPassthrough
+	vtDCSIgnore
+	vtSOSPMAPCString
+)
+
+// C0 and C1 control bytes with special meaning to the state machine.
+const (
+	ctrlBEL = 0x07
+	ctrlCAN = 0x18
+	ctrlSUB = 0x1a
+	ctrlESC = 0x1b
+	c1DCS   = 0x90
+	c1SOS   = 0x98
+	c1ST    = 0x9c
+	c1OSC   = 0x9d
+	c1PM    = 0x9e
+	c1APC   = 0x9f
+)
+
+// errIncomplete is returned by Parse when seq ends in the middle of a
+// multi-byte character.
+var errIncomplete = errors.New("incomplete character sequence")
+
+// isStringState reports whether s is inside a control string (OSC, DCS,
+// SOS, PM or APC), which ends only at a string terminator.
+func isStringState(s vtState) bool {
+	return s >= vtOSCString
+}
+
+// scan runs the state machine over buf[start:], starting in state from,
+// and returns the length of the first complete token in buf. If buf ends
+// before the token does, complete is false, n is len(buf) and end is the
+// state reached.
+//
+// Resynchronization rules: ESC always starts a new sequence, ending any
+// sequence in progress just before it (inside a control string ESC \ is
+// the string terminator); CAN and SUB abort a control string; and any byte
+// that cannot continue a CSI, SS3 or escape sequence ends that sequence
+// just before the byte.
+//
+//nolint:cyclop // A state machine is clearest as a single switch
+func (p *SequenceParser) scan(buf []byte, start int, from vtState) (n int, complete bool, end vtState) {
+	state := from
+	for i := start; i < len(buf); i++ {
+		b := buf[i]
+
+		switch state {
+		case vtEscape:
+			switch {
+			case b == '[':
+				state = vtCSIEntry
+			case b == 'O':
+				state = vtSS3
+			case b == ']':
+				state = vtOSCString
+			case b == 'P':
+				state = vtDCSEntry
+			case b == 'X' || b == '^' || b == '_':
+				state = vtSOSPMAPCString
+			case b >= 0x20 && b <= 0x2f:
+				state = vtEscapeIntermediate
+			case b == ctrlESC:
+				// ESC ESC: the first one stands alone
+				return i, true, vtGround
+			case b >= 0x80:
+				// Alt + non-ASCII character
+				size, ok := p.textLength(buf[i:])
+				if !ok {
+					return len(buf), false, vtEscape
+				}
+				return i + size, true, vtGround
+			default:
+				// Alt + key: ESC followed by one printable or control byte
+				return i + 1, true, vtGround
+			}
+
+		case vtEscapeIntermediate:
+			switch {
+			case b >= 0x20 && b <= 0x2f:
+			case b >= 0x30 && b <= 0x7e:
+				return i + 1, true, vtGround
+			default:
+				return i, true, vtGround
+			}
+
+		case vtCSIEntry, vtCSIParam, vtCSIIntermediate, vtCSIIgnore:
+			switch {
+			case b >= 0x40 && b <= 0x7e:
+				if state == vtCSIEntry && b == '[' {
+					// Linux console function keys: ESC [ [ A
+					state = vtLinuxFunction
+					continue
+				}
+				return i + 1, true, vtGround
+			case b >= 0x30 && b <= 0x3f:
+				if state == vtCSIIntermediate {
+					state = vtCSIIgnore
+				} else if state != vtCSIIgnore {
+					state = vtCSIParam
+				}
+			case b >= 0x20 && b <= 0x2f:
+				if state != vtCSIIgnore {
+					state = vtCSIIntermediate
+				}
+			default:
+				// Malformed - end the sequence before the offending byte
+				return i, true, vtGround
+			}
+
+		case vtSS3:
+			switch {
+			case b >= 0x40 && b <= 0x7e:
+				return i + 1, true, vtGround
+			case b >= 0x30 && b <= 0x3f:
+				// Some terminals send modifier parameters: ESC O 2 P
+			default:
+				return i, true, vtGround
+			}
+
+		case vtLinuxFunction:
+			if b >= 0x40 && b <= 0x7e {
+				return i + 1, true, vtGround
+			}
+			return i, true, vtGround
+
+		case vtDCSEntry, vtDCSParam, vtDCSIntermediate:
+			switch {
+			case b >= 0x30 && b <= 0x3f:
+				if state == vtDCSIntermediate {
+					state = vtDCSIgnore
+				} else {
+					state = vtDCSParam
+				}
+			case b >= 0x20 && b <= 0x2f:
+				state = vtDCSIntermediate
+			case b >= 0x40 && b <= 0x7e:
+				state = vtDCSPassthrough
+			default:
+				if n, done := p.scanStringControl(buf, i); done {
+					return n, true, vtGround
+				} else if n < 0 {
+					return len(buf), false, state
+				}
+				state = vtDCSIgnore
+			}
+
+		case vtOSCString, vtDCSPassthrough, vtDCSIgnore, vtSOSPMAPCString:
+			if state == vtOSCString && b == ctrlBEL {
+				return i + 1, true, vtGround
+			}
+			if n, done := p.scanStringControl(buf, i); done {
+				return n, true, vtGround
+			} else if n < 0 {
+				return len(buf), false, state
+			}
+		}
+	}
+	return len(buf), false, state
+}
+
+// scanStringControl handles the bytes that can end a control string.
+// It returns (n, true) when the string ends with a token of length n,
+// (-1, false) when buf ends on an ESC that may start a terminator, and
+// (0, false) when buf[i] is ordinary string content.
+func (p *SequenceParser) scanStringControl(buf []byte, i int) (int, bool) {
+	switch b := buf[i]; {
+	case b == ctrlCAN || b == ctrlSUB:
+		// Abort: the control is consumed along with the string
+		return i + 1, true
+	case b == ctrlESC:
+		if i+1 == len(buf) {
+			return -1, false
+		}
+		if buf[i+1] == '\\' {
+			return i + 2, true
+		}
+		// Any other escape aborts the string and starts a new sequence
+		return i, true
+	case b == c1ST && p.cfg.c1Controls:
+		return i + 1, true
+	}
+	return 0, false
+}
+
+// scanGround returns the length of the token starting at buf[0] in the
+// ground state.
+func (p *SequenceParser) scanGround(buf []byte) (n int, complete bool, end vtState) {
+	b := buf[0]
+
+	if b == ctrlESC {
+		return p.scan(buf, 1, vtEscape)
+	}
+
+	if p.isC1(b) {
+		var next vtState
+		switch b {
+		case c1CSI:
+			next = vtCSIEntry
+		case c1SS3:
+			next = vtSS3
+		case c1OSC:
+			next = vtOSCString
+		case c1DCS:
+			next = vtDCSEntry
+		case c1SOS, c1PM, c1APC:
+			next = vtSOSPMAPCString
+		default:
+			return 1, true, vtGround
+		}
+		return p.scan(buf, 1, next)
+	}
+
+	if b >= 0x80 && p.cfg.highBitMeta {
+		return 1, true, vtGround
+	}
+
+	if p.cfg.graphemeClusters && isTextByte(b) && p.charset() == UTF8 {
+		n, ok := graphemeClusterLength(buf)
+		return n, ok, vtGround
+	}
+
+	if b >= 0x80 {
+		size, ok := p.textLength(buf)
+		if !ok {
+			return len(buf), false, vtGround
+		}
+		return size, true, vtGround
+	}
+
+	return 1, true, vtGround
+}
+
+// textLength returns the length of the character at the start of buf in
+// the configured charset, or false if buf ends inside it.
+func (p *SequenceParser) textLength(buf []byte) (int, bool) {
+	cs := p.charset()
+	if !cs.FullRune(buf) {
+		return len(buf), false
+	}
+	_, size := cs.DecodeRune(buf)
+	return size, true
+}
+
+// split returns the length of the first sequence in buf. complete is false
+// if buf ends before that sequence does and more bytes should be read
+// before parsing it.
+func (p *SequenceParser) split(buf []byte) (n int, complete bool) {
+	if len(buf) == 0 {
+		return 0, false
+	}
+	n, complete, _ = p.scanFrom(buf, p.resume)
+	return n, complete
+}
+
+// scanFrom returns the first token in buf, starting in state from.
+// A state other than vtGround continues a sequence whose beginning was
+// consumed by an earlier call.
+func (p *SequenceParser) scanFrom(buf []byte, from vtState) (n int, complete bool, end vtState) {
+	if from == vtGround {
+		return p.scanGround(buf)
+	}
+	return p.scan(buf, 0, from)
+}
+
+// decode returns the first event in buf and the number of bytes it
+// consumed. n == 0 means buf holds only the beginning of a sequence and
+// more input is needed. When flush is set no more input is expected soon
+// (the escape timeout expired), so a partial sequence is decoded as is:
+// a lone ESC is the Escape key and ESC followed by an introducer is Alt
+// plus that key.
+//
+// decode returns errNoEvent for sequences that carry no key, such as
+// terminal replies in control strings; callers skip them.
+func (p *SequenceParser) decode(buf []byte, flush bool) (event Event, n int, err error) {
+	if len(buf) == 0 {
+		return Event{}, 0, nil
+	}
+
+	resume := p.resume
+	n, complete, end := p.scanFrom(buf, resume)
+
+	switch {
+	case complete:
+		p.resume = vtGround
+		if resume != vtGround {
+			// Tail of an overlong sequence already reported
+			return Event{}, n, errNoEvent
+		}
+		event, err = p.Parse(buf[:n])
+		return p.withRaw(event, err, buf[:n]), n, err
+
+	case len(buf) >= maxSequenceLength && end != vtGround:
+		// Overlong sequence: consume what we have and keep discarding in
+		// the same state until it ends
+		p.resume = overflowState(end)
+		if resume != vtGround || isStringState(end) {
+			return Event{}, len(buf), errNoEvent
+		}
+		return p.withRaw(p.unknownEvent(), nil, buf), len(buf), nil
+
+	case !flush && len(buf) < maxSequenceLength:
+		return Event{}, 0, nil
+	}
+
+	// Flush a partial sequence
+	p.resume = vtGround
+	if resume != vtGround || isStringState(end) && !isBareIntroducer(buf) {
+		return Event{}, len(buf), errNoEvent
+	}
+	event, err = p.Parse(buf)
+	if errors.Is(err, errIncomplete) {
+		event, err = p.unknownEvent(), nil
+		event.Rune = utf8.RuneError
+	}
+	return p.withRaw(event, err, buf), len(buf), err
+}
+
+// withRaw returns event with a copy of raw, the bytes it was decoded from,
+// if raw bytes were requested (WithRawBytes) or the event is unrecognized
+// input, including bytes that are not valid UTF-8. Other events, and sequences that decoded to no event (err set),
+// are returned unchanged so decoding stays allocation-free.
+func (p *SequenceParser) withRaw(event Event, err error, raw []byte) Event {
+	if err == nil && (p.cfg.rawBytes || event.Key == KeyUnknown && (event.Rune == 0 || event.Rune == utf8.RuneError)) {
+		event.Raw = string(raw)
+	}
+	return event
+}
+
+// overflowState returns the state in which to continue discarding a
+// sequence that exceeded maxSequenceLength.
+func overflowState(s vtState) vtState {
+	switch s {
+	case vtCSIEntry, vtCSIParam, vtCSIIntermediate, vtCSIIgnore:
+		return vtCSIIgnore
+	case vtDCSEntry, vtDCSParam, vtDCSIntermediate:
+		return vtDCSIgnore
+	}
+	return s
+}
+
+// isBareIntroducer reports whether buf is ESC followed by a single
+// introducer byte, which on timeout means Alt plus that key.
+func isBareIntroducer(buf []byte) bool {
+	return len(buf) == 2 && buf[0] == ctrlESC
+}
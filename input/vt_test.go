@@ -0,0 +1,279 @@
+package input
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestSplit validates splitting of buffered input into individual
+// sequences, so multiple sequences delivered by one read are not merged.
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name         string
+		buf          string
+		wantN        int
+		wantComplete bool
+	}{
+		{"empty", "", 0, false},
+		{"ASCII", "ab", 1, true},
+		{"lone ESC", "\x1b", 1, false},
+		{"double ESC", "\x1b\x1b", 1, true},
+		{"CSI arrow", "\x1b[Ax", 3, true},
+		{"CSI tilde", "\x1b[15~\x1b[A", 5, true},
+		{"CSI modifier", "\x1b[1;5Ax", 6, true},
+		{"partial CSI", "\x1b[1", 3, false},
+		{"malformed CSI", "\x1b[1\x01", 3, true},
+		{"CSI interrupted by ESC", "\x1b[1\x1b[A", 3, true},
+		{"SS3", "\x1bOPq", 3, true},
+		{"partial SS3", "\x1bO", 2, false},
+		{"Linux console F1", "\x1b[[Ax", 4, true},
+		{"OSC with BEL", "\x1b]11;rgb:0/0/0\x07a", 15, true},
+		{"OSC with ST", "\x1b]0;t\x1b\\a", 7, true},
+		{"partial OSC", "\x1b]0;title", 9, false},
+		{"OSC ending in ESC", "\x1b]0;t\x1b", 6, false},
+		{"OSC aborted by CAN", "\x1b]0;t\x18a", 6, true},
+		{"OSC interrupted by ESC", "\x1b]0;t\x1b[A", 5, true},
+		{"DCS", "\x1bP1$r0m\x1b\\a", 9, true},
+		{"APC", "\x1b_Gi=1\x1b\\", 8, true},
+		{"nF escape", "\x1b(Ba", 3, true},
+		{"Alt+a", "\x1bab", 2, true},
+		{"Alt+é", "\x1bé", 3, true},
+		{"win32 input pair", "\x1b[65;30;97;1;0;1_\x1b[65;30;97;0;0;1_", 17, true},
+		{"UTF-8", "é!", 2, true},
+		{"partial UTF-8", "\xe6\x97", 2, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, complete := NewSequenceParser().split([]byte(tt.buf))
+			if n != tt.wantN || complete != tt.wantComplete {
+				t.Errorf("split(%q) = (%d, %v), want (%d, %v)",
+					tt.buf, n, complete, tt.wantN, tt.wantComplete)
+			}
+		})
+	}
+}
+
+// decodeAll decodes every event in buf as the backend does, flushing
+// whatever remains at the end.
+func decodeAll(t *testing.T, p *SequenceParser, buf []byte) []Event {
+	t.Helper()
+	var events []Event
+	for len(buf) > 0 {
+		event, n, err := p.decode(buf, false)
+		if n == 0 {
+			event, n, err = p.decode(buf, true)
+		}
+		if n <= 0 || n > len(buf) {
+			t.Fatalf("decode(%q) consumed %d bytes", buf, n)
+		}
+		buf = buf[n:]
+		if errors.Is(err, errNoEvent) {
+			continue
+		}
+		if err != nil {
+			t.Fatalf("decode error: %v", err)
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+// TestDecode validates that buffered input decodes to the expected keys,
+// with terminal replies in control strings dropped.
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name     string
+		buf      string
+		wantKeys []Key
+	}{
+		{"arrows", "\x1b[A\x1b[B", []Key{KeyUp, KeyDown}},
+		{"lone ESC", "\x1b", []Key{KeyEscape}},
+		{"Alt+]", "\x1b]", []Key{KeyUnknown}},
+		{"Alt+P", "\x1bP", []Key{KeyP}},
+		{"OSC reply dropped", "\x1b]11;rgb:ffff/ffff/ffff\x1b\\x", []Key{KeyX}},
+		{"DCS reply dropped", "\x1bP1$r0m\x1b\\\x1b[C", []Key{KeyRight}},
+		{"truncated CSI", "\x1b[1;", []Key{KeyUnknown}},
+		{"invalid UTF-8", "\xff", []Key{KeyUnknown}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := decodeAll(t, NewSequenceParser(), []byte(tt.buf))
+			if len(events) != len(tt.wantKeys) {
+				t.Fatalf("got %d events %v, want %v", len(events), events, tt.wantKeys)
+			}
+			for i, want := range tt.wantKeys {
+				if events[i].Key != want {
+					t.Errorf("event %d: Key = %v, want %v", i, events[i].Key, want)
+				}
+			}
+		})
+	}
+}
+
+// TestDecodeOverlong validates that sequences longer than maxSequenceLength
+// are discarded in pieces without desynchronizing the stream.
+func TestDecodeOverlong(t *testing.T) {
+	long := make([]byte, 0, 512)
+	long = append(long, "\x1b[1"...)
+	for i := 0; i < 300; i++ {
+		long = append(long, ';', '1')
+	}
+	long = append(long, "~\x1b[A"...)
+
+	osc := append([]byte("\x1b]52;c;"), make([]byte, 300)...)
+	for i := 7; i < len(osc); i++ {
+		osc[i] = 'A'
+	}
+	osc = append(osc, "\x07\x1b[B"...)
+
+	tests := []struct {
+		name     string
+		buf      []byte
+		wantKeys []Key
+	}{
+		{"overlong CSI", long, []Key{KeyUnknown, KeyUp}},
+		{"overlong OSC", osc, []Key{KeyDown}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewSequenceParser()
+			var events []Event
+			// Feed the input in maxSequenceLength chunks, as the backend
+			// buffer would hold it
+			for buf := tt.buf; len(buf) > 0; {
+				chunk := buf
+				if len(chunk) > maxSequenceLength {
+					chunk = chunk[:maxSequenceLength]
+				}
+				got := decodeAll(t, p, chunk)
+				events = append(events, got...)
+				buf = buf[len(chunk):]
+			}
+
+			var keys []Key
+			for _, e := range events {
+				keys = append(keys, e.Key)
+			}
+			if len(keys) != len(tt.wantKeys) {
+				t.Fatalf("got keys %v, want %v", keys, tt.wantKeys)
+			}
+			for i := range keys {
+				if keys[i] != tt.wantKeys[i] {
+					t.Errorf("got keys %v, want %v", keys, tt.wantKeys)
+					break
+				}
+			}
+		})
+	}
+}
+
+// TestDecodeNoAllocs validates that decoding common key sequences, and
+// sequences that carry no key, does not allocate.
+func TestDecodeNoAllocs(t *testing.T) {
+	p := NewSequenceParser()
+	inputs := [][]byte{
+		[]byte("a"),
+		[]byte("\x1b[A"),
+		[]byte("\x1b[1;5C"),
+		[]byte("\x1b[15~"),
+		[]byte("\x1bOP"),
+		[]byte("é"),
+		[]byte("\x1b[16;42;0;1;16;1_"),
+		[]byte("\x1b]11;rgb:0/0/0\x07"),
+		[]byte("\x1bP1$r0m\x1b\\"),
+	}
+
+	for _, in := range inputs {
+		allocs := testing.AllocsPerRun(100, func() {
+			_, _, _ = p.decode(in, false)
+		})
+		if allocs != 0 {
+			t.Errorf("decode(%q) allocated %.0f times, want 0", in, allocs)
+		}
+	}
+}
+
+// TestDecodeRaw validates which events carry the raw bytes they were
+// decoded from.
+func TestDecodeRaw(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []Option
+		buf     string
+		flush   bool
+		wantRaw string
+	}{
+		{"known key has none", nil, "\x1b[A", false, ""},
+		{"text has none", nil, "é", false, ""},
+		{"unknown CSI", nil, "\x1b[5q", false, "\x1b[5q"},
+		{"flushed partial", nil, "\x1b[1;", true, "\x1b[1;"},
+		{"invalid UTF-8", nil, "\xff", false, "\xff"},
+		{"flushed Latin-1 byte", nil, "\xe9", true, "\xe9"},
+		{"flushed truncated UTF-8", nil, "\xc3", true, "\xc3"},
+		{"all events with option", []Option{WithRawBytes()}, "\x1b[1;5C", false, "\x1b[1;5C"},
+		{"ASCII with option", []Option{WithRawBytes()}, "a", false, "a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := []byte(tt.buf)
+			event, n, err := NewSequenceParser(tt.opts...).decode(buf, tt.flush)
+			if err != nil || n != len(buf) {
+				t.Fatalf("decode(%q) = (%d, %v), want (%d, nil)", tt.buf, n, err, len(buf))
+			}
+			if event.Raw != tt.wantRaw {
+				t.Errorf("Raw = %q, want %q", event.Raw, tt.wantRaw)
+			}
+		})
+	}
+}
+
+// FuzzDecode checks that decoding arbitrary input never panics, always
+// makes progress, and resynchronizes: after a CAN and a fresh sequence,
+// the next key is decoded correctly regardless of preceding garbage.
+func FuzzDecode(f *testing.F) {
+	seeds := []string{
+		"", "a", "\x1b", "\x1b[", "\x1b[1;5A", "\x1b]0;title\x07",
+		"\x1bP1$r\x1b\\", "\x1b[<0;1;1M", "\x9b1;2A", "\xe6\x97\xa5",
+		"\x1b[65;30;97;1;0;1_", "\x1b\x1b[A", "\x1bO2P",
+	}
+	for _, s := range seeds {
+		f.Add([]byte(s))
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		for _, opts := range [][]Option{
+			nil,
+			{WithC1Controls()},
+			{WithGraphemeClusters()},
+		} {
+			p := NewSequenceParser(opts...)
+			buf := append(append([]byte{}, data...), "\x18\x1b[A"...)
+
+			var last Event
+			for len(buf) > 0 {
+				chunk := buf
+				if len(chunk) > maxSequenceLength {
+					chunk = chunk[:maxSequenceLength]
+				}
+				event, n, err := p.decode(chunk, false)
+				if n == 0 {
+					event, n, err = p.decode(chunk, true)
+				}
+				if n <= 0 || n > len(chunk) {
+					t.Fatalf("decode(%q) consumed %d bytes", chunk, n)
+				}
+				buf = buf[n:]
+				if err == nil {
+					last = event
+				}
+			}
+			if last.Key != KeyUp || last.Modifiers != ModNone {
+				t.Fatalf("after %q: last event %+v, want KeyUp", data, last)
+			}
+		}
+	})
+}
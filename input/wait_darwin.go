@@ -0,0 +1,46 @@
+//go:build darwin
+// +build darwin
+
+package input
+
+import "golang.org/x/sys/unix"
+
+// waitSet holds the descriptors wait selects on.
+type waitSet unix.FdSet
+
+// wait blocks with select(2) until the terminal or the wake pipe is
+// readable, or until ms milliseconds have passed (forever if ms < 0). It
+// reports whether the terminal has input and whether Cancel woke it;
+// select cannot tell a hangup apart, but a read then returns end of file.
+//
+// macOS poll(2) does not support devices, so a terminal would never be
+// reported readable; select does.
+func (b *unixBackend) wait(ms int) (ready, hangup, woken bool, err error) {
+	if b.fd >= unix.FD_SETSIZE || b.wakeR >= unix.FD_SETSIZE {
+		return false, false, false, unix.EINVAL
+	}
+
+	set := (*unix.FdSet)(&b.waitFds)
+	set.Zero()
+	set.Set(b.fd)
+	nfd := b.fd + 1
+	if b.wakeR >= 0 {
+		set.Set(b.wakeR)
+		nfd = max(nfd, b.wakeR+1)
+	}
+
+	var timeout *unix.Timeval
+	if ms >= 0 {
+		tv := unix.NsecToTimeval(int64(ms) * 1e6)
+		timeout = &tv
+	}
+
+	n, err := unix.Select(nfd, set, nil, nil, timeout)
+	switch {
+	case err != nil || n == 0:
+		return false, false, false, err
+	case b.wakeR >= 0 && set.IsSet(b.wakeR):
+		return false, false, true, nil
+	}
+	return set.IsSet(b.fd), false, false, nil
+}
@@ -0,0 +1,40 @@
+//go:build linux
+// +build linux
+
+package input
+
+import "golang.org/x/sys/unix"
+
+// waitSet holds the descriptors wait polls.
+type waitSet [2]unix.PollFd
+
+// wait blocks with poll(2) until the terminal or the wake pipe is readable,
+// or until ms milliseconds have passed (forever if ms < 0). It reports
+// whether the terminal has input, whether it hung up and whether Cancel
+// woke it.
+func (b *unixBackend) wait(ms int) (ready, hangup, woken bool, err error) {
+	fds := b.waitFds[:1]
+	fds[0] = unix.PollFd{Fd: int32(b.fd), Events: unix.POLLIN}
+	if b.wakeR >= 0 {
+		fds = b.waitFds[:2]
+		fds[1] = unix.PollFd{Fd: int32(b.wakeR), Events: unix.POLLIN}
+	}
+
+	n, err := unix.Poll(fds, ms)
+	switch {
+	case err != nil || n == 0:
+		return false, false, false, err
+	case len(fds) > 1 && fds[1].Revents != 0:
+		return false, false, true, nil
+	case fds[0].Revents&unix.POLLNVAL != 0:
+		// Reading would block forever instead of failing
+		return false, false, false, unix.EBADF
+	case fds[0].Revents&unix.POLLHUP != 0:
+		// The terminal went away, as on an SSH hangup (POLLERR is set
+		// too); read what it left, then end of file
+		return true, true, false, nil
+	case fds[0].Revents&unix.POLLERR != 0:
+		return false, false, false, unix.EIO
+	}
+	return fds[0].Revents != 0, false, false, nil
+}
@@ -0,0 +1,110 @@
+//go:build linux || darwin
+// +build linux darwin
+
+package input
+
+import (
+	"errors"
+	"io"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// The Unix backend waits for input on the terminal and on the read end of
+// a self-pipe (see wait in wait_linux.go and wait_darwin.go). Cancel writes
+// a byte to the pipe, so a blocked ReadEvent returns at once instead of
+// after the next key press, and the escape timeout is simply the wait
+// timeout.
+
+// openWake creates the self-pipe used by Cancel.
+func (b *unixBackend) openWake() error {
+	if b.wakeR >= 0 {
+		return nil
+	}
+	var p [2]int
+	if err := unix.Pipe(p[:]); err != nil {
+		return err
+	}
+	for _, fd := range p {
+		unix.CloseOnExec(fd)
+		if err := unix.SetNonblock(fd, true); err != nil {
+			_ = unix.Close(p[0])
+			_ = unix.Close(p[1])
+			return err
+		}
+	}
+	b.wakeR, b.wakeW = p[0], p[1]
+	return nil
+}
+
+// closeWake closes the self-pipe.
+func (b *unixBackend) closeWake() {
+	if b.wakeR < 0 {
+		return
+	}
+	_ = unix.Close(b.wakeR)
+	_ = unix.Close(b.wakeW)
+	b.wakeR, b.wakeW = -1, -1
+}
+
+// Cancel makes a blocked ReadEvent return io.EOF immediately, as do all
+// later calls until the backend is initialized again. It is safe to call
+// from any goroutine between Init and Restore.
+func (b *unixBackend) Cancel() {
+	if b.wakeW < 0 {
+		return
+	}
+	// The pipe stays readable until Restore; a full pipe is fine too
+	_, _ = unix.Write(b.wakeW, []byte{0})
+}
+
+// read waits up to timeout for input (forever if timeout < 0) and reads it
+// into buf. It returns 0 and a nil error when the timeout expires, and
+// io.EOF after Cancel or when the input is closed or the terminal hung up.
+func (b *unixBackend) read(buf []byte, timeout time.Duration) (int, error) {
+	ms := -1
+	if timeout >= 0 {
+		ms = int((timeout + time.Millisecond - 1) / time.Millisecond)
+	}
+
+	for {
+		ready, hangup, woken, err := b.wait(ms)
+		switch {
+		case errors.Is(err, unix.EINTR):
+			continue
+		case err != nil:
+			return 0, err
+		case woken:
+			return 0, io.EOF
+		case !ready:
+			return 0, nil
+		}
+
+		n, err := unix.Read(b.fd, buf)
+		switch {
+		case hangup && (n <= 0 || errors.Is(err, unix.EIO)):
+			// Linux reports EIO reading a terminal that hung up
+			return 0, io.EOF
+		case errors.Is(err, unix.EINTR), errors.Is(err, unix.EAGAIN):
+			// Spurious wakeup: poll again
+			continue
+		case err != nil:
+			return 0, err
+		case n == 0:
+			return 0, io.EOF
+		}
+		return n, nil
+	}
+}
+
+// discardTypeahead drops input that is already waiting to be read.
+func (b *unixBackend) discardTypeahead() {
+	var buf [256]byte
+	for {
+		n, err := b.read(buf[:], 0)
+		if n == 0 || err != nil {
+			return
+		}
+	}
+}
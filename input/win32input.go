@@ -0,0 +1,222 @@
+package input
+
+import (
+	"errors"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Win32 input mode (DECSET 9001) is a Windows Terminal / ConPTY extension
+// that reports every KEY_EVENT_RECORD as a CSI sequence:
+//
+//	ESC [ Vk ; Sc ; Uc ; Kd ; Cs ; Rc _
+//
+// Vk is the virtual key code, Sc the scan code, Uc the UTF-16 code unit of
+// the translated character, Kd is 1 for key-down and 0 for key-up, Cs the
+// control key state bitmask and Rc the repeat count. Omitted parameters
+// default to 0, except Rc which defaults to 1.
+
+// Control key state bits reported in the Cs parameter.
+const (
+	win32RightAltPressed  = 0x0001
+	win32LeftAltPressed   = 0x0002
+	win32RightCtrlPressed = 0x0004
+	win32LeftCtrlPressed  = 0x0008
+	win32ShiftPressed     = 0x0010
+)
+
+// Virtual key codes that need explicit mapping.
+const (
+	vkBack    = 0x08
+	vkTab     = 0x09
+	vkReturn  = 0x0d
+	vkShift   = 0x10
+	vkControl = 0x11
+	vkMenu    = 0x12
+	vkCapital = 0x14
+	vkEscape  = 0x1b
+	vkSpace   = 0x20
+	vkPrior   = 0x21
+	vkNext    = 0x22
+	vkEnd     = 0x23
+	vkHome    = 0x24
+	vkLeft    = 0x25
+	vkUp      = 0x26
+	vkRight   = 0x27
+	vkDown    = 0x28
+	vkInsert  = 0x2d
+	vkDelete  = 0x2e
+	vkLWin    = 0x5b
+	vkRWin    = 0x5c
+	vkF1      = 0x70
+	vkF12     = 0x7b
+	vkNumLock = 0x90
+	vkScroll  = 0x91
+	vkLShift  = 0xa0
+	vkRMenu   = 0xa5
+)
+
+// win32Final is the final byte of a win32 input mode sequence.
+const win32Final = '_'
+
+// errNoEvent is returned by Parse for sequences that were decoded
+// successfully but do not produce an event on their own, such as the
+// key events of modifier keys or the first half of a UTF-16 surrogate pair.
+// Backends skip these sequences and continue reading.
+var errNoEvent = errors.New("sequence produces no event")
+
+// isWin32InputSequence reports whether seq has the shape of a win32 input
+// mode sequence: ESC [ followed by digits and semicolons, terminated by '_'.
+func isWin32InputSequence(seq []byte) bool {
+	if len(seq) < 3 || seq[0] != 0x1b || seq[1] != '[' || seq[len(seq)-1] != win32Final {
+		return false
+	}
+	for _, b := range seq[2 : len(seq)-1] {
+		if (b < '0' || b > '9') && b != ';' {
+			return false
+		}
+	}
+	return true
+}
+
+// parseWin32Input decodes a win32 input mode sequence into event.
+// The caller must have checked the sequence with isWin32InputSequence.
+func (p *SequenceParser) parseWin32Input(seq []byte, event Event) (Event, error) {
+	// Defaults per the ConPTY specification.
+	params := [6]int{0, 0, 0, 0, 0, 1}
+	idx := 0
+	for _, b := range seq[2 : len(seq)-1] {
+		if b == ';' {
+			idx++
+			if idx >= len(params) {
+				break
+			}
+			params[idx] = 0
+			continue
+		}
+		// Clamp to keep overlong parameters from overflowing.
+		if params[idx] < 1<<20 {
+			params[idx] = params[idx]*10 + int(b-'0')
+		}
+	}
+	vk, uc, kd, cs, rc := params[0], params[2], params[3], params[4], params[5]
+
+	event.Pressed = kd != 0
+
+	// Track which virtual keys are held so autorepeated key-downs are
+	// flagged even when the terminal reports a repeat count of 1.
+	if vk >= 0 && vk < len(p.win32Held) {
+		event.Repeat = event.Pressed && (rc > 1 || p.win32Held[vk])
+		p.win32Held[vk] = event.Pressed
+	}
+
+	// Modifier and lock keys carry no key code of their own; their state
+	// is reported through the Cs field of the events that follow.
+	switch {
+	case vk == vkShift, vk == vkControl, vk == vkMenu, vk == vkCapital,
+		vk == vkLWin, vk == vkRWin, vk == vkNumLock, vk == vkScroll,
+		vk >= vkLShift && vk <= vkRMenu:
+		return Event{}, errNoEvent
+	}
+
+	if cs&win32ShiftPressed != 0 {
+		event.Modifiers |= ModShift
+	}
+	if cs&(win32LeftAltPressed|win32RightAltPressed) != 0 {
+		event.Modifiers |= ModAlt
+	}
+	if cs&(win32LeftCtrlPressed|win32RightCtrlPressed) != 0 {
+		event.Modifiers |= ModCtrl
+	}
+
+	// Characters outside the BMP arrive as two events, one per surrogate.
+	r := rune(uc)
+	switch {
+	case utf16.IsSurrogate(r) && r < 0xdc00:
+		if event.Pressed {
+			p.win32Surrogate = r
+		}
+		return Event{}, errNoEvent
+	case utf16.IsSurrogate(r):
+		r = utf16.DecodeRune(p.win32Surrogate, r)
+		p.win32Surrogate = 0
+	}
+
+	// AltGr is reported as Right Alt + Left Ctrl. When it produced a
+	// character, the modifiers were consumed by the layout.
+	if r >= 0x20 && event.Modifiers&(ModCtrl|ModAlt) == ModCtrl|ModAlt {
+		event.Modifiers &^= ModCtrl | ModAlt
+	}
+
+	event.Key = p.win32VKToKey(vk, r, event.Modifiers)
+	switch event.Key {
+	case KeyTab:
+		event.Rune = '\t'
+	case KeyEnter:
+		event.Rune = '\r'
+	case KeySpace:
+		event.Rune = ' '
+	default:
+		if r >= 0x20 && r != 0x7f && r != utf8.RuneError {
+			event.Rune = r
+		}
+	}
+
+	return event, nil
+}
+
+// win32VKToKey maps a virtual key code to a normalized Key. The translated
+// character r is used for keys without a dedicated virtual key mapping.
+func (p *SequenceParser) win32VKToKey(vk int, r rune, mods Modifier) Key {
+	switch {
+	case vk >= 'A' && vk <= 'Z':
+		// Match the control-byte path: Ctrl+letter is reported as KeyCtrlX.
+		if mods&ModCtrl != 0 && mods&ModAlt == 0 {
+			return Key(int(KeyCtrlA) + vk - 'A')
+		}
+		return Key(int(KeyA) + vk - 'A')
+	case vk >= '0' && vk <= '9':
+		return Key(int(Key0) + vk - '0')
+	case vk >= vkF1 && vk <= vkF12:
+		return Key(int(KeyF1) + vk - vkF1)
+	}
+
+	switch vk {
+	case vkBack:
+		return KeyBackspace
+	case vkTab:
+		return KeyTab
+	case vkReturn:
+		return KeyEnter
+	case vkEscape:
+		return KeyEscape
+	case vkSpace:
+		return KeySpace
+	case vkPrior:
+		return KeyPageUp
+	case vkNext:
+		return KeyPageDown
+	case vkEnd:
+		return KeyEnd
+	case vkHome:
+		return KeyHome
+	case vkLeft:
+		return KeyLeft
+	case vkUp:
+		return KeyUp
+	case vkRight:
+		return KeyRight
+	case vkDown:
+		return KeyDown
+	case vkInsert:
+		return KeyInsert
+	case vkDelete:
+		return KeyDelete
+	}
+
+	// VK_PACKET (0), OEM punctuation keys, etc.: fall back to the character.
+	if r >= 0x20 && r <= 0x7e {
+		return p.runeToKey(r)
+	}
+	return KeyUnknown
+}
@@ -0,0 +1,56 @@
+package contract_test
+
+import (
+	"testing"
+
+	"github.com/dshills/gokeys/input"
+)
+
+// TestModifiedKeySequences validates that xterm-style modifier parameters
+// (CSI 1 ; m final, CSI n ; m ~, SS3 m final) are decoded into Modifiers.
+func TestModifiedKeySequences(t *testing.T) {
+	tests := []struct {
+		name     string
+		sequence string
+		wantKey  input.Key
+		wantMods input.Modifier
+	}{
+		{"Shift+Up", "\x1b[1;2A", input.KeyUp, input.ModShift},
+		{"Alt+Down", "\x1b[1;3B", input.KeyDown, input.ModAlt},
+		{"Ctrl+Right", "\x1b[1;5C", input.KeyRight, input.ModCtrl},
+		{"Ctrl+Shift+Left", "\x1b[1;6D", input.KeyLeft, input.ModCtrl | input.ModShift},
+		{"Meta+Home", "\x1b[1;9H", input.KeyHome, input.ModAlt},
+		{"Shift+Delete", "\x1b[3;2~", input.KeyDelete, input.ModShift},
+		{"Ctrl+F5", "\x1b[15;5~", input.KeyF5, input.ModCtrl},
+		{"Shift+F1 (SS3)", "\x1bO2P", input.KeyF1, input.ModShift},
+		{"F12", "\x1b[24~", input.KeyF12, input.ModNone},
+		{"rxvt Home", "\x1b[7~", input.KeyHome, input.ModNone},
+		{"Linux console F3", "\x1b[[C", input.KeyF3, input.ModNone},
+		{"Shift+Tab", "\x1b[Z", input.KeyTab, input.ModShift},
+		{"Keypad Enter", "\x1bOM", input.KeyEnter, input.ModNone},
+		{"Alt+x", "\x1bx", input.KeyX, input.ModAlt},
+		{"Alt+Escape", "\x1b\x1b", input.KeyEscape, input.ModAlt},
+		{"Tab", "\t", input.KeyTab, input.ModNone},
+		{"Enter", "\r", input.KeyEnter, input.ModNone},
+		{"sub-parameters ignored", "\x1b[1:2;5A", input.KeyUp, input.ModCtrl},
+		{"mouse report", "\x1b[<0;10;5M", input.KeyUnknown, input.ModNone},
+		{"intermediate byte", "\x1b[1 q", input.KeyUnknown, input.ModNone},
+	}
+
+	parser := input.NewSequenceParser()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := parser.Parse([]byte(tt.sequence))
+			if err != nil {
+				t.Fatalf("Parse(%q) error: %v", tt.sequence, err)
+			}
+			if event.Key != tt.wantKey {
+				t.Errorf("Key = %v, want %v", event.Key, tt.wantKey)
+			}
+			if event.Modifiers != tt.wantMods {
+				t.Errorf("Modifiers = %v, want %v", event.Modifiers, tt.wantMods)
+			}
+		})
+	}
+}
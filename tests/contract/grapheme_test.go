@@ -0,0 +1,57 @@
+package contract_test
+
+import (
+	"testing"
+
+	"github.com/dshills/gokeys/input"
+)
+
+// TestGraphemeClusterEvents validates that, with grapheme clustering
+// enabled, multi-code-point characters are delivered as one event whose
+// Text carries the whole cluster.
+func TestGraphemeClusterEvents(t *testing.T) {
+	parser := input.NewSequenceParser(input.WithGraphemeClusters())
+
+	tests := []struct {
+		name     string
+		text     string
+		wantRune rune
+		wantKey  input.Key
+	}{
+		{"ASCII letter", "a", 'a', input.KeyA},
+		{"e + combining acute", "é", 'e', input.KeyE},
+		{"family emoji", "👨‍👩‍👧", '👨', input.KeyUnknown},
+		{"flag", "🇯🇵", '🇯', input.KeyUnknown},
+		{"precomposed", "é", 'é', input.KeyUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := parser.Parse([]byte(tt.text))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if event.Text != tt.text {
+				t.Errorf("Text = %q, want %q", event.Text, tt.text)
+			}
+			if event.Rune != tt.wantRune {
+				t.Errorf("Rune = %q, want %q", event.Rune, tt.wantRune)
+			}
+			if event.Key != tt.wantKey {
+				t.Errorf("Key = %v, want %v", event.Key, tt.wantKey)
+			}
+		})
+	}
+}
+
+// TestGraphemeClusteringDisabledByDefault validates that Text stays empty
+// unless clustering is requested.
+func TestGraphemeClusteringDisabledByDefault(t *testing.T) {
+	event, err := input.NewSequenceParser().Parse([]byte("a"))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if event.Text != "" {
+		t.Errorf("Text = %q, want empty", event.Text)
+	}
+}
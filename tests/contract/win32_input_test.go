@@ -0,0 +1,123 @@
+package contract_test
+
+import (
+	"testing"
+
+	"github.com/dshills/gokeys/input"
+)
+
+// TestWin32InputModeDecoding validates that win32 input mode sequences
+// (CSI Vk;Sc;Uc;Kd;Cs;Rc _) sent by Windows Terminal and ConPTY are
+// decoded into normalized key, rune, modifier and press state.
+func TestWin32InputModeDecoding(t *testing.T) {
+	tests := []struct {
+		name        string
+		sequence    string
+		wantKey     input.Key
+		wantRune    rune
+		wantMods    input.Modifier
+		wantPressed bool
+	}{
+		{"a down", "\x1b[65;30;97;1;0;1_", input.KeyA, 'a', input.ModNone, true},
+		{"a up", "\x1b[65;30;97;0;0;1_", input.KeyA, 'a', input.ModNone, false},
+		{"Shift+A", "\x1b[65;30;65;1;16;1_", input.KeyA, 'A', input.ModShift, true},
+		{"Ctrl+C", "\x1b[67;46;3;1;8;1_", input.KeyCtrlC, 0, input.ModCtrl, true},
+		{"Alt+X", "\x1b[88;45;120;1;2;1_", input.KeyX, 'x', input.ModAlt, true},
+		{"AltGr produces @", "\x1b[81;16;64;1;9;1_", input.KeyQ, '@', input.ModNone, true},
+		{"Up arrow", "\x1b[38;72;0;1;256;1_", input.KeyUp, 0, input.ModNone, true},
+		{"Ctrl+Left", "\x1b[37;75;0;1;264;1_", input.KeyLeft, 0, input.ModCtrl, true},
+		{"F5", "\x1b[116;63;0;1;0;1_", input.KeyF5, 0, input.ModNone, true},
+		{"Escape", "\x1b[27;1;27;1;0;1_", input.KeyEscape, 0, input.ModNone, true},
+		{"Enter", "\x1b[13;28;13;1;0;1_", input.KeyEnter, '\r', input.ModNone, true},
+		{"Tab", "\x1b[9;15;9;1;0;1_", input.KeyTab, '\t', input.ModNone, true},
+		{"Space", "\x1b[32;57;32;1;0;1_", input.KeySpace, ' ', input.ModNone, true},
+		{"Backspace", "\x1b[8;14;8;1;0;1_", input.KeyBackspace, 0, input.ModNone, true},
+		{"Digit", "\x1b[55;8;55;1;0;1_", input.Key7, '7', input.ModNone, true},
+		{"Non-ASCII via VK_PACKET", "\x1b[0;0;233;1;0;1_", input.KeyUnknown, 'é', input.ModNone, true},
+		{"Omitted parameters", "\x1b[46;;;1_", input.KeyDelete, 0, input.ModNone, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := input.NewSequenceParser()
+			event, err := parser.Parse([]byte(tt.sequence))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if event.Key != tt.wantKey {
+				t.Errorf("Key = %v, want %v", event.Key, tt.wantKey)
+			}
+			if event.Rune != tt.wantRune {
+				t.Errorf("Rune = %q, want %q", event.Rune, tt.wantRune)
+			}
+			if event.Modifiers != tt.wantMods {
+				t.Errorf("Modifiers = %v, want %v", event.Modifiers, tt.wantMods)
+			}
+			if event.Pressed != tt.wantPressed {
+				t.Errorf("Pressed = %v, want %v", event.Pressed, tt.wantPressed)
+			}
+		})
+	}
+}
+
+// TestWin32InputModeRepeat validates that held keys are flagged as repeats,
+// both from the repeat count and from consecutive key-downs.
+func TestWin32InputModeRepeat(t *testing.T) {
+	parser := input.NewSequenceParser()
+
+	steps := []struct {
+		sequence   string
+		wantRepeat bool
+	}{
+		{"\x1b[65;30;97;1;0;1_", false}, // first press
+		{"\x1b[65;30;97;1;0;1_", true},  // autorepeat while held
+		{"\x1b[65;30;97;0;0;1_", false}, // release
+		{"\x1b[65;30;97;1;0;1_", false}, // new press
+		{"\x1b[65;30;97;1;0;3_", true},  // explicit repeat count
+	}
+
+	for i, step := range steps {
+		event, err := parser.Parse([]byte(step.sequence))
+		if err != nil {
+			t.Fatalf("step %d: Parse() error = %v", i, err)
+		}
+		if event.Repeat != step.wantRepeat {
+			t.Errorf("step %d: Repeat = %v, want %v", i, event.Repeat, step.wantRepeat)
+		}
+	}
+}
+
+// TestWin32InputModeSurrogatePair validates that characters outside the
+// BMP, which arrive as one event per UTF-16 surrogate, are combined.
+func TestWin32InputModeSurrogatePair(t *testing.T) {
+	parser := input.NewSequenceParser()
+
+	// 😀 U+1F600 = D83D DE00
+	if _, err := parser.Parse([]byte("\x1b[0;0;55357;1;0;1_")); err == nil {
+		t.Error("high surrogate should not produce an event on its own")
+	}
+
+	event, err := parser.Parse([]byte("\x1b[0;0;56832;1;0;1_"))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if event.Rune != '😀' {
+		t.Errorf("Rune = %q, want %q", event.Rune, '😀')
+	}
+}
+
+// TestWin32InputModeModifierKeys validates that standalone modifier key
+// reports do not produce events.
+func TestWin32InputModeModifierKeys(t *testing.T) {
+	parser := input.NewSequenceParser()
+
+	for _, seq := range []string{
+		"\x1b[16;42;0;1;16;1_", // Shift down
+		"\x1b[17;29;0;1;8;1_",  // Ctrl down
+		"\x1b[18;56;0;1;2;1_",  // Alt down
+	} {
+		if _, err := parser.Parse([]byte(seq)); err == nil {
+			t.Errorf("Parse(%q) should not produce an event", seq)
+		}
+	}
+}